@@ -1,15 +1,30 @@
 package agent
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
+
+	"github.com/archviz/backend-go/agent/render"
+)
+
+// Renderer selects which backend SimpleAgent uses to turn DOT IR into SVG.
+const (
+	RendererAuto     = "auto"     // prefer graphviz if found on PATH, else pure-Go
+	RendererPureGo   = "pure-go"  // always use the built-in Sugiyama-style layout
+	RendererGraphviz = "graphviz" // shell out to `dot` on PATH; pure-Go if it is missing or fails
 )
 
 type AgentConfig struct {
 	RootDir string
+	// Renderer is one of RendererAuto, RendererPureGo, RendererGraphviz,
+	// or a filesystem path to a `dot`-compatible binary (implies graphviz).
+	// Empty defaults to RendererAuto.
+	Renderer string
 }
 
 // SimpleAgent provides basic diagram generation, IR and rendering.
@@ -34,28 +49,119 @@ func (a *SimpleAgent) SaveSVG(imageId, diagramType, svg string) (string, error)
 	return filePath, nil
 }
 
-func (a *SimpleAgent) Generate(imageType string) (map[string]interface{}, error) {
-	id := fmt.Sprintf("gox%016x", uint64(time.Now().UnixNano()))
-	svg := GenerateSVG(id, imageType)
-	path, err := a.SaveSVG(id, imageType, svg)
-	if err != nil {
-		return nil, err
-	}
-	return map[string]interface{}{
-		"image_id":      id,
-		"image_version": 1,
-		"file_path":     strings.TrimPrefix(path, a.cfg.RootDir),
-		"svg":           svg,
-	}, nil
+// idCounter is mixed into generated IDs so a tight loop generating several
+// diagrams (e.g. one ingestion run producing component/package/deployment
+// diagrams back to back) can't collide on the same nanosecond timestamp.
+var idCounter uint64
+
+func nextImageID() string {
+	n := atomic.AddUint64(&idCounter, 1)
+	return fmt.Sprintf("gox%016x", uint64(time.Now().UnixNano())^n)
 }
 
+// GetIR returns the stored IR for id if one was produced by StoreIR (e.g.
+// by repo ingestion), otherwise a placeholder digraph.
 func (a *SimpleAgent) GetIR(id string) map[string]interface{} {
+	if stored, ok := a.loadStoredIR(id); ok {
+		return stored
+	}
 	return map[string]interface{}{
 		"id": id,
 		"ir": fmt.Sprintf("digraph %s { A -> B }", id),
 	}
 }
 
+// StoredIR returns the DOT previously persisted for id via StoreIR, or
+// ok=false if none exists. Unlike GetIR, it never substitutes the
+// placeholder digraph - for callers (like GIF export's prior-version
+// lookup) that need to tell "no IR was ever stored for this id" apart
+// from "here's its real IR".
+func (a *SimpleAgent) StoredIR(id string) (dot string, ok bool) {
+	stored, ok := a.loadStoredIR(id)
+	if !ok {
+		return "", false
+	}
+	dot, ok = stored["ir"].(string)
+	return dot, ok
+}
+
+// StoreIR persists dot as the IR for id under outputs/<id>.ir.json so a
+// later GetIR(id) returns it instead of the placeholder digraph.
+func (a *SimpleAgent) StoreIR(id, dot string) error {
+	outputsDir := filepath.Join(a.cfg.RootDir, "outputs")
+	if err := os.MkdirAll(outputsDir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(map[string]string{"id": id, "ir": dot})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outputsDir, id+".ir.json"), data, 0o644)
+}
+
+func (a *SimpleAgent) loadStoredIR(id string) (map[string]interface{}, bool) {
+	data, err := os.ReadFile(filepath.Join(a.cfg.RootDir, "outputs", id+".ir.json"))
+	if err != nil {
+		return nil, false
+	}
+	var stored map[string]interface{}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, false
+	}
+	return stored, true
+}
+
+// StoreGeneratedDiagram assigns a fresh image ID to dot, persists it as
+// that image's IR, and renders + saves the SVG - for pipelines (like repo
+// ingestion) that produce DOT IR directly rather than through Generate's
+// placeholder.
+func (a *SimpleAgent) StoreGeneratedDiagram(diagramType, dot string) (string, error) {
+	id := nextImageID()
+	if err := a.StoreIR(id, dot); err != nil {
+		return "", err
+	}
+	svg, err := a.RenderDOT(dot)
+	if err != nil {
+		return "", err
+	}
+	if _, err := a.SaveSVG(id, diagramType, svg); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// RenderDOT turns DOT source into SVG using the configured renderer:
+// graphviz when available and selected, otherwise the pure-Go layered
+// layout in the render subpackage. Invalid DOT falls back to the static
+// placeholder so callers always get a usable SVG back.
+func (a *SimpleAgent) RenderDOT(dot string) (string, error) {
+	if bin := a.graphvizBin(); bin != "" {
+		if svg, err := render.RenderWithGraphviz(bin, dot); err == nil {
+			return svg, nil
+		}
+	}
+	g, err := render.ParseDOT(dot)
+	if err != nil {
+		return GenerateSVG("", "component"), nil
+	}
+	return render.RenderSVG(render.ComputeLayout(g)), nil
+}
+
+// graphvizBin resolves a.cfg.Renderer to a usable `dot` binary path, or ""
+// if the pure-Go renderer should be used.
+func (a *SimpleAgent) graphvizBin() string {
+	switch a.cfg.Renderer {
+	case "", RendererAuto:
+		return render.DetectGraphvizBin("")
+	case RendererPureGo:
+		return ""
+	case RendererGraphviz:
+		return render.DetectGraphvizBin("dot")
+	default:
+		return render.DetectGraphvizBin(a.cfg.Renderer)
+	}
+}
+
 func GenerateSVG(id, diagramType string) string {
 	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
 <svg width="300" height="140" viewBox="0 0 300 140" xmlns="http://www.w3.org/2000/svg">
@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/archviz/backend-go/agent/gifexport"
+	"github.com/archviz/backend-go/agent/render"
+)
+
+// GIFOptions controls animated GIF export: how many interpolated frames to
+// render between the "from" and "to" diagrams, the per-frame delay, and an
+// optional canvas override (0 means size to the laid-out content).
+type GIFOptions struct {
+	Frames  int
+	DelayMS int
+	Width   int
+	Height  int
+}
+
+const (
+	defaultGIFFrames  = 12
+	defaultGIFDelayMS = 120
+)
+
+func (o GIFOptions) withDefaults() GIFOptions {
+	if o.Frames <= 0 {
+		o.Frames = defaultGIFFrames
+	}
+	if o.DelayMS <= 0 {
+		o.DelayMS = defaultGIFDelayMS
+	}
+	return o
+}
+
+// ExportGIF renders the transition from fromDOT to toDOT as an animated
+// GIF: nodes shared by both diagrams slide to their new position, added
+// nodes/edges fade in and removed ones fade out. The GIF is written under
+// outputs/<id>_<diagramType>_<version>.gif, mirroring the SVG naming used
+// by SaveSVG so filepath.Glob callers can look up either by id.
+func (a *SimpleAgent) ExportGIF(id, diagramType, fromDOT, toDOT string, opts GIFOptions) (string, error) {
+	opts = opts.withDefaults()
+
+	fromGraph, err := render.ParseDOT(fromDOT)
+	if err != nil {
+		return "", fmt.Errorf("agent: parsing from_ir: %w", err)
+	}
+	toGraph, err := render.ParseDOT(toDOT)
+	if err != nil {
+		return "", fmt.Errorf("agent: parsing to_ir: %w", err)
+	}
+
+	fromLayout := render.ComputeLayout(fromGraph)
+	toLayout := render.ComputeLayout(toGraph)
+	frames := gifexport.BuildFrames(fromLayout, toLayout, opts.Frames)
+
+	width, height := opts.Width, opts.Height
+	if width == 0 {
+		width = int(maxF(fromLayout.Width, toLayout.Width))
+	}
+	if height == 0 {
+		height = int(maxF(fromLayout.Height, toLayout.Height))
+	}
+
+	data, err := gifexport.Encode(frames, width, height, opts.DelayMS/10)
+	if err != nil {
+		return "", fmt.Errorf("agent: encoding gif: %w", err)
+	}
+
+	outputsDir := filepath.Join(a.cfg.RootDir, "outputs")
+	if err := os.MkdirAll(outputsDir, 0o755); err != nil {
+		return "", err
+	}
+	fileName := fmt.Sprintf("%s_%s_1.gif", id, sanitize(diagramType))
+	filePath := filepath.Join(outputsDir, fileName)
+	if err := os.WriteFile(filePath, data, 0o644); err != nil {
+		return "", err
+	}
+	return filePath, nil
+}
+
+func maxF(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,37 @@
+package gifexport
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	gifcodec "image/gif"
+)
+
+const maxPaletteColors = 256
+
+// Encode rasterizes and quantizes frames into a single animated GIF,
+// sharing one median-cut palette across all frames so colors stay
+// consistent as the diagram transitions. delayCS is the per-frame delay
+// in the 100ths-of-a-second units the GIF format uses.
+func Encode(frames []Frame, width, height, delayCS int) ([]byte, error) {
+	rasters := make([]*image.RGBA, len(frames))
+	for i, f := range frames {
+		rasters[i] = Rasterize(f, width, height)
+	}
+	pal := medianCutPalette(rasters, maxPaletteColors)
+
+	g := &gifcodec.GIF{}
+	for _, r := range rasters {
+		paletted := image.NewPaletted(r.Bounds(), pal)
+		draw.Draw(paletted, paletted.Bounds(), r, image.Point{}, draw.Src)
+		g.Image = append(g.Image, paletted)
+		g.Delay = append(g.Delay, delayCS)
+		g.Disposal = append(g.Disposal, gifcodec.DisposalBackground)
+	}
+
+	var buf bytes.Buffer
+	if err := gifcodec.EncodeAll(&buf, g); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
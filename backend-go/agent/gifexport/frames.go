@@ -0,0 +1,145 @@
+// Package gifexport turns a transition between two rendered diagram
+// layouts into an animated GIF: nodes fade/slide in, added edges fade in,
+// and removed nodes and edges fade out. Edges are drawn whole in every
+// frame; they are not drawn out along their length.
+package gifexport
+
+import "github.com/archviz/backend-go/agent/render"
+
+// FrameNode is a LayoutNode positioned and faded for a single frame.
+type FrameNode struct {
+	render.LayoutNode
+	Opacity float64
+}
+
+// FrameEdge is a LayoutEdge faded for a single frame.
+type FrameEdge struct {
+	render.LayoutEdge
+	Opacity float64
+}
+
+// Frame is one still of the animation: every node/edge that appears in
+// either the "from" or "to" layout, at its interpolated position/opacity
+// for this step of the transition.
+type Frame struct {
+	Width, Height float64
+	Nodes         []FrameNode
+	Edges         []FrameEdge
+}
+
+// BuildFrames interpolates n frames (n >= 2) from the "from" layout to the
+// "to" layout. Nodes present in both layouts slide from their old position
+// to their new one. Nodes only in "to" fade in over the first half of the
+// sequence; nodes only in "from" fade out over the first half too, so
+// outgoing nodes are gone at the midpoint, the same frame in which
+// incoming ones reach full opacity. Edges get their opacity from their
+// own presence, not their endpoints': edges in both layouts stay opaque,
+// edges only in "to" fade in and edges only in "from" fade out, on the
+// same schedule as nodes.
+func BuildFrames(from, to *render.Layout, n int) []Frame {
+	if n < 2 {
+		n = 2
+	}
+	fromNodes := indexNodes(from.Nodes)
+	toNodes := indexNodes(to.Nodes)
+
+	frames := make([]Frame, n)
+	width, height := maxF(from.Width, to.Width), maxF(from.Height, to.Height)
+	for i := 0; i < n; i++ {
+		t := float64(i) / float64(n-1)
+		frame := Frame{Width: width, Height: height}
+
+		for id, tn := range toNodes {
+			fn, existedBefore := fromNodes[id]
+			var ln render.LayoutNode
+			opacity := 1.0
+			switch {
+			case existedBefore:
+				ln = lerpNode(fn, tn, t)
+			default:
+				ln = tn
+				opacity = fadeIn(t)
+			}
+			frame.Nodes = append(frame.Nodes, FrameNode{LayoutNode: ln, Opacity: opacity})
+		}
+		for id, fn := range fromNodes {
+			if _, stillPresent := toNodes[id]; stillPresent {
+				continue
+			}
+			frame.Nodes = append(frame.Nodes, FrameNode{LayoutNode: fn, Opacity: fadeOut(t)})
+		}
+
+		present := func(id string) bool {
+			_, okFrom := fromNodes[id]
+			_, okTo := toNodes[id]
+			return okFrom || okTo
+		}
+		for _, e := range to.Edges {
+			if !present(e.From) || !present(e.To) {
+				continue
+			}
+			opacity := 1.0
+			if _, existed := edgeIn(from.Edges, e.From, e.To); !existed {
+				opacity = fadeIn(t)
+			}
+			frame.Edges = append(frame.Edges, FrameEdge{LayoutEdge: e, Opacity: opacity})
+		}
+		for _, e := range from.Edges {
+			if _, stillPresent := edgeIn(to.Edges, e.From, e.To); stillPresent {
+				continue
+			}
+			frame.Edges = append(frame.Edges, FrameEdge{LayoutEdge: e, Opacity: fadeOut(t)})
+		}
+
+		frames[i] = frame
+	}
+	return frames
+}
+
+func indexNodes(nodes []render.LayoutNode) map[string]render.LayoutNode {
+	m := make(map[string]render.LayoutNode, len(nodes))
+	for _, n := range nodes {
+		m[n.ID] = n
+	}
+	return m
+}
+
+func edgeIn(edges []render.LayoutEdge, from, to string) (render.LayoutEdge, bool) {
+	for _, e := range edges {
+		if e.From == from && e.To == to {
+			return e, true
+		}
+	}
+	return render.LayoutEdge{}, false
+}
+
+func lerpNode(a, b render.LayoutNode, t float64) render.LayoutNode {
+	b.X = a.X + (b.X-a.X)*t
+	b.Y = a.Y + (b.Y-a.Y)*t
+	return b
+}
+
+// fadeIn ramps 0->1 over the first half of the sequence, reaching full
+// opacity by the midpoint so incoming nodes are settled well before "done".
+func fadeIn(t float64) float64 {
+	if t >= 0.5 {
+		return 1
+	}
+	return t / 0.5
+}
+
+// fadeOut ramps 1->0 over the first half of the sequence so outgoing nodes
+// are gone by the midpoint, when incoming ones reach full opacity.
+func fadeOut(t float64) float64 {
+	if t >= 0.5 {
+		return 0
+	}
+	return 1 - t/0.5
+}
+
+func maxF(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
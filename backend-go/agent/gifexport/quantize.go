@@ -0,0 +1,131 @@
+package gifexport
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+type rgb struct{ r, g, b uint8 }
+
+// medianCutPalette builds a palette of up to maxColors by recursively
+// splitting the set of distinct pixel colors along its widest channel,
+// the classic median-cut quantization algorithm.
+func medianCutPalette(imgs []*image.RGBA, maxColors int) color.Palette {
+	counts := map[rgb]int{}
+	for _, img := range imgs {
+		b := img.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				c := img.RGBAAt(x, y)
+				counts[rgb{c.R, c.G, c.B}]++
+			}
+		}
+	}
+	buckets := make([]rgb, 0, len(counts))
+	for c := range counts {
+		buckets = append(buckets, c)
+	}
+	if len(buckets) <= maxColors {
+		pal := make(color.Palette, len(buckets))
+		for i, c := range buckets {
+			pal[i] = color.RGBA{R: c.r, G: c.g, B: c.b, A: 255}
+		}
+		return pal
+	}
+
+	groups := [][]rgb{buckets}
+	for len(groups) < maxColors {
+		// split the group with the widest channel range along that channel
+		widest, widestRange := 0, -1
+		for i, g := range groups {
+			if len(g) < 2 {
+				continue
+			}
+			if r := channelRange(g); r > widestRange {
+				widest, widestRange = i, r
+			}
+		}
+		if widestRange <= 0 {
+			break
+		}
+		g := groups[widest]
+		ch := widestChannel(g)
+		sort.Slice(g, func(i, j int) bool { return channel(g[i], ch) < channel(g[j], ch) })
+		mid := len(g) / 2
+		groups[widest] = g[:mid]
+		groups = append(groups, g[mid:])
+	}
+
+	pal := make(color.Palette, 0, len(groups))
+	for _, g := range groups {
+		if len(g) == 0 {
+			continue
+		}
+		var rs, gs, bs int
+		for _, c := range g {
+			rs += int(c.r)
+			gs += int(c.g)
+			bs += int(c.b)
+		}
+		n := len(g)
+		pal = append(pal, color.RGBA{R: uint8(rs / n), G: uint8(gs / n), B: uint8(bs / n), A: 255})
+	}
+	return pal
+}
+
+func channel(c rgb, ch int) uint8 {
+	switch ch {
+	case 0:
+		return c.r
+	case 1:
+		return c.g
+	default:
+		return c.b
+	}
+}
+
+func channelRange(g []rgb) int {
+	_, rr, gr, br := channelBounds(g)
+	return maxInt(maxInt(rr, gr), br)
+}
+
+func widestChannel(g []rgb) int {
+	widest, _, _, _ := channelBounds(g)
+	return widest
+}
+
+// channelBounds returns the widest channel index (0=r,1=g,2=b) along with
+// the per-channel ranges, computed in one pass over the group.
+func channelBounds(g []rgb) (widest, rr, gr, br int) {
+	minR, maxR := 255, 0
+	minG, maxG := 255, 0
+	minB, maxB := 255, 0
+	for _, c := range g {
+		minR, maxR = minInt(minR, int(c.r)), maxInt(maxR, int(c.r))
+		minG, maxG = minInt(minG, int(c.g)), maxInt(maxG, int(c.g))
+		minB, maxB = minInt(minB, int(c.b)), maxInt(maxB, int(c.b))
+	}
+	rr, gr, br = maxR-minR, maxG-minG, maxB-minB
+	widest = 0
+	if gr > rr && gr >= br {
+		widest = 1
+	} else if br > rr && br > gr {
+		widest = 2
+	}
+	return
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
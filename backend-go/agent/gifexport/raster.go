@@ -0,0 +1,120 @@
+package gifexport
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// Rasterize draws a Frame directly onto an RGBA canvas. Rather than
+// round-trip through a generic SVG path rasterizer (oksvg/rasterx) it
+// draws the laid-out shapes straight onto the image: every node as a
+// filled square-cornered rectangle and every edge as a one-pixel polyline.
+// Node labels, edge labels, arrowheads, rounded corners and ellipse
+// shapes from the SVG renderer are not drawn.
+func Rasterize(f Frame, width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	for _, e := range f.Edges {
+		drawPolyline(img, e.Points, blend(mustParseColor(e.Color), e.Opacity))
+	}
+	for _, n := range f.Nodes {
+		drawRoundedRect(img, n.X, n.Y, n.W, n.H, blend(mustParseColor(n.Color), n.Opacity))
+	}
+	return img
+}
+
+func drawRoundedRect(img *image.RGBA, x, y, w, h float64, c color.RGBA) {
+	x0, y0, x1, y1 := int(x), int(y), int(x+w), int(y+h)
+	for py := y0; py < y1; py++ {
+		for px := x0; px < x1; px++ {
+			if px < 0 || py < 0 || px >= img.Bounds().Dx() || py >= img.Bounds().Dy() {
+				continue
+			}
+			setBlended(img, px, py, c)
+		}
+	}
+}
+
+func drawPolyline(img *image.RGBA, pts [][2]float64, c color.RGBA) {
+	for i := 0; i+1 < len(pts); i++ {
+		drawLine(img, pts[i][0], pts[i][1], pts[i+1][0], pts[i+1][1], c)
+	}
+}
+
+// drawLine uses a simple DDA walk; our edges are axis-aligned or near it,
+// so sub-pixel anti-aliasing isn't worth the complexity here.
+func drawLine(img *image.RGBA, x0, y0, x1, y1 float64, c color.RGBA) {
+	steps := int(math.Max(math.Abs(x1-x0), math.Abs(y1-y0)))
+	if steps == 0 {
+		steps = 1
+	}
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		px := int(x0 + (x1-x0)*t)
+		py := int(y0 + (y1-y0)*t)
+		if px < 0 || py < 0 || px >= img.Bounds().Dx() || py >= img.Bounds().Dy() {
+			continue
+		}
+		setBlended(img, px, py, c)
+	}
+}
+
+func setBlended(img *image.RGBA, x, y int, c color.RGBA) {
+	if c.A == 255 {
+		img.SetRGBA(x, y, c)
+		return
+	}
+	bg := img.RGBAAt(x, y)
+	a := float64(c.A) / 255
+	blend := func(fg, bg uint8) uint8 {
+		return uint8(float64(fg)*a + float64(bg)*(1-a))
+	}
+	img.SetRGBA(x, y, color.RGBA{
+		R: blend(c.R, bg.R),
+		G: blend(c.G, bg.G),
+		B: blend(c.B, bg.B),
+		A: 255,
+	})
+}
+
+func blend(c color.RGBA, opacity float64) color.RGBA {
+	if opacity < 0 {
+		opacity = 0
+	}
+	if opacity > 1 {
+		opacity = 1
+	}
+	c.A = uint8(255 * opacity)
+	return c
+}
+
+// mustParseColor accepts "#rrggbb" hex colors (as produced by the
+// renderer); anything else falls back to a neutral indigo.
+func mustParseColor(s string) color.RGBA {
+	if len(s) == 7 && s[0] == '#' {
+		r := fromHex(s[1:3])
+		g := fromHex(s[3:5])
+		b := fromHex(s[5:7])
+		return color.RGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: 255}
+	}
+	return color.RGBA{R: 0x4f, G: 0x46, B: 0xe5, A: 255}
+}
+
+func fromHex(s string) int {
+	v := 0
+	for _, r := range s {
+		v *= 16
+		switch {
+		case r >= '0' && r <= '9':
+			v += int(r - '0')
+		case r >= 'a' && r <= 'f':
+			v += int(r-'a') + 10
+		case r >= 'A' && r <= 'F':
+			v += int(r-'A') + 10
+		}
+	}
+	return v
+}
@@ -0,0 +1,221 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ParseDOT parses a (subset of) Graphviz DOT source into a typed Graph.
+// It supports digraph/graph headers, node and edge statements, bracketed
+// attribute lists, quoted identifiers and `subgraph cluster_x { ... }`
+// blocks. It does not aim to be a full DOT grammar, only enough of one to
+// cover the IR this codebase emits and hand-written IR a user might paste.
+func ParseDOT(src string) (*Graph, error) {
+	toks := tokenizeDOT(src)
+	p := &dotParser{toks: toks}
+	return p.parse()
+}
+
+type dotParser struct {
+	toks []string
+	pos  int
+}
+
+func (p *dotParser) peek() string {
+	if p.pos >= len(p.toks) {
+		return ""
+	}
+	return p.toks[p.pos]
+}
+
+func (p *dotParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *dotParser) parse() (*Graph, error) {
+	g := newGraph()
+
+	if strings.EqualFold(p.peek(), "strict") {
+		p.next()
+	}
+	switch strings.ToLower(p.peek()) {
+	case "digraph":
+		g.Directed = true
+		p.next()
+	case "graph":
+		g.Directed = false
+		p.next()
+	default:
+		return nil, fmt.Errorf("render: expected 'digraph' or 'graph', got %q", p.peek())
+	}
+	if p.peek() != "{" {
+		// optional graph name
+		p.next()
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	if err := p.parseStmts(g, nil); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (p *dotParser) expect(tok string) error {
+	if p.peek() != tok {
+		return fmt.Errorf("render: expected %q, got %q", tok, p.peek())
+	}
+	p.next()
+	return nil
+}
+
+// parseStmts consumes statements up to the matching '}'. If cluster is
+// non-nil, plain node statements are also recorded as cluster members.
+func (p *dotParser) parseStmts(g *Graph, cluster *Cluster) error {
+	for {
+		switch t := p.peek(); t {
+		case "":
+			return fmt.Errorf("render: unexpected EOF inside graph body")
+		case "}":
+			p.next()
+			return nil
+		case ";":
+			p.next()
+		case "subgraph":
+			p.next()
+			id := ""
+			if p.peek() != "{" {
+				id = p.next()
+			}
+			sub := &Cluster{ID: id}
+			if err := p.expect("{"); err != nil {
+				return err
+			}
+			if err := p.parseStmts(g, sub); err != nil {
+				return err
+			}
+			g.Clusters = append(g.Clusters, sub)
+		default:
+			lhs := p.next()
+			if strings.ToLower(lhs) == "node" || strings.ToLower(lhs) == "edge" || strings.ToLower(lhs) == "graph" {
+				// default-attribute statement (e.g. `node [shape=box]`) - skip.
+				if p.peek() == "[" {
+					if _, err := p.parseAttrList(); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if p.peek() == "->" || p.peek() == "--" {
+				p.next()
+				rhs := p.next()
+				attrs := Attrs{}
+				if p.peek() == "[" {
+					var err error
+					attrs, err = p.parseAttrList()
+					if err != nil {
+						return err
+					}
+				}
+				g.node(lhs)
+				g.node(rhs)
+				g.Edges = append(g.Edges, &Edge{From: lhs, To: rhs, Attrs: attrs})
+				if cluster != nil {
+					cluster.Nodes = append(cluster.Nodes, lhs, rhs)
+				}
+				continue
+			}
+			// plain node statement, optionally with attrs
+			attrs := Attrs{}
+			if p.peek() == "[" {
+				var err error
+				attrs, err = p.parseAttrList()
+				if err != nil {
+					return err
+				}
+			}
+			n := g.node(lhs)
+			for k, v := range attrs {
+				n.Attrs[k] = v
+			}
+			if cluster != nil {
+				cluster.Nodes = append(cluster.Nodes, lhs)
+			}
+		}
+	}
+}
+
+func (p *dotParser) parseAttrList() (Attrs, error) {
+	attrs := Attrs{}
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+	for p.peek() != "]" {
+		if p.peek() == "" {
+			return nil, fmt.Errorf("render: unexpected EOF inside attribute list")
+		}
+		key := p.next()
+		if err := p.expect("="); err != nil {
+			return nil, err
+		}
+		val := p.next()
+		attrs[strings.ToLower(key)] = val
+		if p.peek() == "," || p.peek() == ";" {
+			p.next()
+		}
+	}
+	p.next() // consume ']'
+	return attrs, nil
+}
+
+// tokenizeDOT splits DOT source into identifiers, quoted strings and
+// punctuation tokens ('{', '}', '[', ']', '->', '--', '=', ',', ';').
+func tokenizeDOT(src string) []string {
+	var toks []string
+	r := []rune(src)
+	i, n := 0, len(r)
+	for i < n {
+		c := r[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '/' && i+1 < n && r[i+1] == '/':
+			for i < n && r[i] != '\n' {
+				i++
+			}
+		case c == '#':
+			for i < n && r[i] != '\n' {
+				i++
+			}
+		case c == '"':
+			j := i + 1
+			var sb strings.Builder
+			for j < n && r[j] != '"' {
+				if r[j] == '\\' && j+1 < n {
+					j++
+				}
+				sb.WriteRune(r[j])
+				j++
+			}
+			toks = append(toks, sb.String())
+			i = j + 1
+		case c == '-' && i+1 < n && (r[i+1] == '>' || r[i+1] == '-'):
+			toks = append(toks, string(c)+string(r[i+1]))
+			i += 2
+		case strings.ContainsRune("{}[]=,;", c):
+			toks = append(toks, string(c))
+			i++
+		default:
+			j := i
+			for j < n && !unicode.IsSpace(r[j]) && !strings.ContainsRune("{}[]=,;\"", r[j]) {
+				j++
+			}
+			toks = append(toks, string(r[i:j]))
+			i = j
+		}
+	}
+	return toks
+}
@@ -0,0 +1,69 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EmitDOT serializes a Graph back to DOT source - the inverse of ParseDOT.
+// It's used by analyzers (like the repo ingestion pipeline) that build a
+// Graph programmatically and need DOT text to store as IR or hand to
+// RenderDOT.
+func EmitDOT(g *Graph) string {
+	var b strings.Builder
+	kind := "graph"
+	op := "--"
+	if g.Directed {
+		kind, op = "digraph", "->"
+	}
+	fmt.Fprintf(&b, "%s {\n", kind)
+
+	clustered := map[string]bool{}
+	for _, c := range g.Clusters {
+		fmt.Fprintf(&b, "  subgraph %s {\n", quoteDOT(c.ID))
+		if len(c.Attrs) > 0 {
+			fmt.Fprintf(&b, "    graph%s;\n", attrListDOT(c.Attrs))
+		}
+		for _, id := range c.Nodes {
+			if n, ok := g.Nodes[id]; ok {
+				b.WriteString("    " + quoteDOT(id) + attrListDOT(n.Attrs) + ";\n")
+				clustered[id] = true
+			}
+		}
+		b.WriteString("  }\n")
+	}
+	for _, id := range g.NodeOrder {
+		if clustered[id] {
+			continue
+		}
+		b.WriteString("  " + quoteDOT(id) + attrListDOT(g.Nodes[id].Attrs) + ";\n")
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s %s %s%s;\n", quoteDOT(e.From), op, quoteDOT(e.To), attrListDOT(e.Attrs))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func attrListDOT(attrs Attrs) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf(`%s=%s`, k, quoteDOT(attrs[k]))
+	}
+	return " [" + strings.Join(parts, ", ") + "]"
+}
+
+func quoteDOT(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
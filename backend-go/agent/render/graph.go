@@ -0,0 +1,86 @@
+// Package render turns a DOT intermediate representation into a laid-out
+// SVG diagram. It implements a small Sugiyama-style layered layout
+// (cycle-break -> layer assignment -> crossing reduction -> coordinate
+// assignment) so that multi-node IR produced by the agent renders as an
+// actual diagram instead of a placeholder.
+package render
+
+// Attrs holds DOT attribute key/value pairs, e.g. shape, label, color.
+type Attrs map[string]string
+
+// Node is a single DOT node statement.
+type Node struct {
+	ID    string
+	Attrs Attrs
+}
+
+// Edge is a single DOT edge statement (A -> B).
+type Edge struct {
+	From, To string
+	Attrs    Attrs
+}
+
+// Cluster is a `subgraph cluster_x { ... }` grouping.
+type Cluster struct {
+	ID    string
+	Nodes []string
+	Attrs Attrs
+}
+
+// Graph is the parsed, typed form of a DOT document.
+type Graph struct {
+	Directed  bool
+	NodeOrder []string
+	Nodes     map[string]*Node
+	Edges     []*Edge
+	Clusters  []*Cluster
+}
+
+func newGraph() *Graph {
+	return &Graph{Nodes: map[string]*Node{}}
+}
+
+// NewGraph creates an empty Graph, for callers that build one
+// programmatically (e.g. the repo ingestion analyzers) instead of parsing
+// it from DOT source.
+func NewGraph(directed bool) *Graph {
+	g := newGraph()
+	g.Directed = directed
+	return g
+}
+
+// Node returns the node with the given ID, creating it (with empty
+// Attrs) if it doesn't exist yet.
+func (g *Graph) Node(id string) *Node {
+	return g.node(id)
+}
+
+// AddEdge appends an edge from -> to, creating either endpoint's node if
+// it isn't already present. A nil attrs is treated as empty.
+func (g *Graph) AddEdge(from, to string, attrs Attrs) *Edge {
+	if attrs == nil {
+		attrs = Attrs{}
+	}
+	g.node(from)
+	g.node(to)
+	e := &Edge{From: from, To: to, Attrs: attrs}
+	g.Edges = append(g.Edges, e)
+	return e
+}
+
+func (g *Graph) node(id string) *Node {
+	if n, ok := g.Nodes[id]; ok {
+		return n
+	}
+	n := &Node{ID: id, Attrs: Attrs{}}
+	g.Nodes[id] = n
+	g.NodeOrder = append(g.NodeOrder, id)
+	return n
+}
+
+func (a Attrs) label() string {
+	if l, ok := a["label"]; ok && l != "" {
+		return l
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+)
+
+// DetectGraphvizBin looks for a usable `dot` binary, mirroring how
+// defaultPythonBin probes the project venv before falling back to PATH:
+// an explicit path wins if it exists and is executable, otherwise "dot" is
+// tried on PATH, otherwise rendering falls back to the pure-Go pipeline.
+func DetectGraphvizBin(explicit string) string {
+	if explicit != "" {
+		if _, err := exec.LookPath(explicit); err == nil {
+			return explicit
+		}
+	}
+	if path, err := exec.LookPath("dot"); err == nil {
+		return path
+	}
+	return ""
+}
+
+// RenderWithGraphviz shells out to the given `dot`-compatible binary to
+// render DOT source as SVG, for callers that prefer Graphviz's layout
+// over the pure-Go fallback.
+func RenderWithGraphviz(bin, dot string) (string, error) {
+	cmd := exec.Command(bin, "-Tsvg")
+	cmd.Stdin = bytes.NewBufferString(dot)
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("render: graphviz failed: %w: %s", err, stderr.String())
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,302 @@
+package render
+
+import "sort"
+
+// LayoutNode is a positioned node ready for SVG emission.
+type LayoutNode struct {
+	ID    string
+	Label string
+	Shape string
+	Color string
+	X, Y  float64
+	W, H  float64
+}
+
+// LayoutEdge is a positioned edge with a routed polyline.
+type LayoutEdge struct {
+	From, To string
+	Color    string
+	Label    string
+	Reversed bool
+	Points   [][2]float64
+}
+
+// Layout is the full laid-out diagram: positioned nodes and routed edges
+// plus the overall canvas size.
+type Layout struct {
+	Width, Height float64
+	Nodes         []LayoutNode
+	Edges         []LayoutEdge
+}
+
+const (
+	nodeWidth   = 140.0
+	nodeHeight  = 50.0
+	layerGapY   = 80.0
+	nodeGapX    = 40.0
+	canvasPadX  = 40.0
+	canvasPadY  = 40.0
+	barycenterN = 4 // ordering sweep iterations
+)
+
+// ComputeLayout runs the layered layout pipeline over g:
+//  1. break cycles by reversing back-edges found via DFS
+//  2. assign layers via longest-path
+//  3. reduce edge crossings via iterative barycenter ordering
+//  4. assign coordinates via a median heuristic
+func ComputeLayout(g *Graph) *Layout {
+	reversed := breakCycles(g)
+	layer := assignLayers(g)
+	layers := orderLayers(g, layer)
+	orderLayers2 := barycenterSweep(g, layers, barycenterN)
+	return assignCoordinates(g, orderLayers2, reversed)
+}
+
+// breakCycles performs a DFS over the graph and returns the set of edges
+// (by index into g.Edges) that lie on a back-edge and must be treated as
+// reversed for layering purposes, so that the remaining graph is acyclic.
+func breakCycles(g *Graph) map[int]bool {
+	reversed := map[int]bool{}
+	const (
+		white = 0
+		gray  = 1
+		black = 2
+	)
+	color := map[string]int{}
+	adj := map[string][]int{} // node -> edge indices leaving it
+	for i, e := range g.Edges {
+		adj[e.From] = append(adj[e.From], i)
+	}
+	var visit func(string)
+	visit = func(u string) {
+		color[u] = gray
+		for _, ei := range adj[u] {
+			e := g.Edges[ei]
+			switch color[e.To] {
+			case white:
+				visit(e.To)
+			case gray:
+				reversed[ei] = true // back-edge: u -> ... -> e.To -> u
+			}
+		}
+		color[u] = black
+	}
+	for _, id := range g.NodeOrder {
+		if color[id] == white {
+			visit(id)
+		}
+	}
+	return reversed
+}
+
+// assignLayers assigns each node a layer number via longest-path: a node's
+// layer is one more than the maximum layer of its parents, with back-edges
+// found by breakCycles counted in the reverse direction.
+func assignLayers(g *Graph) map[string]int {
+	layer := map[string]int{}
+	for _, id := range g.NodeOrder {
+		layer[id] = 0
+	}
+	reversed := breakCycles(g)
+	// iterate until fixed point (graph is small; this always converges
+	// quickly since cycles were already broken for layering purposes)
+	changed := true
+	for iter := 0; changed && iter < len(g.NodeOrder)+1; iter++ {
+		changed = false
+		for i, e := range g.Edges {
+			from, to := e.From, e.To
+			if reversed[i] {
+				from, to = to, from
+			}
+			if from == to {
+				continue // self-loops don't constrain layering
+			}
+			if layer[to] < layer[from]+1 {
+				layer[to] = layer[from] + 1
+				changed = true
+			}
+		}
+	}
+	return layer
+}
+
+// orderLayers buckets nodes by layer, preserving original declaration
+// order as the initial ordering within each layer.
+func orderLayers(g *Graph, layer map[string]int) [][]string {
+	maxLayer := 0
+	for _, l := range layer {
+		if l > maxLayer {
+			maxLayer = l
+		}
+	}
+	layers := make([][]string, maxLayer+1)
+	for _, id := range g.NodeOrder {
+		l := layer[id]
+		layers[l] = append(layers[l], id)
+	}
+	return layers
+}
+
+// barycenterSweep reduces edge crossings by repeatedly reordering each
+// layer according to the mean position of its neighbors in the adjacent
+// layer, alternating top-down and bottom-up passes.
+func barycenterSweep(g *Graph, layers [][]string, iterations int) [][]string {
+	pos := func(l []string) map[string]int {
+		m := make(map[string]int, len(l))
+		for i, id := range l {
+			m[id] = i
+		}
+		return m
+	}
+	neighbors := func(id string, fromLower bool) []string {
+		var out []string
+		for _, e := range g.Edges {
+			if fromLower && e.To == id {
+				out = append(out, e.From)
+			} else if !fromLower && e.From == id {
+				out = append(out, e.To)
+			}
+		}
+		return out
+	}
+	reorder := func(layer []string, posAbove map[string]int, useAbove bool) []string {
+		type scored struct {
+			id    string
+			score float64
+			has   bool
+		}
+		items := make([]scored, len(layer))
+		for i, id := range layer {
+			ns := neighbors(id, useAbove)
+			if len(ns) == 0 {
+				items[i] = scored{id: id, score: float64(i), has: false}
+				continue
+			}
+			sum := 0.0
+			for _, nb := range ns {
+				sum += float64(posAbove[nb])
+			}
+			items[i] = scored{id: id, score: sum / float64(len(ns)), has: true}
+		}
+		sort.SliceStable(items, func(a, b int) bool { return items[a].score < items[b].score })
+		out := make([]string, len(items))
+		for i, it := range items {
+			out[i] = it.id
+		}
+		return out
+	}
+	for it := 0; it < iterations; it++ {
+		if it%2 == 0 {
+			for l := 1; l < len(layers); l++ {
+				layers[l] = reorder(layers[l], pos(layers[l-1]), true)
+			}
+		} else {
+			for l := len(layers) - 2; l >= 0; l-- {
+				layers[l] = reorder(layers[l], pos(layers[l+1]), false)
+			}
+		}
+	}
+	return layers
+}
+
+// assignCoordinates lays out each layer left-to-right and stacks layers
+// top-to-bottom, nudging nodes toward the median x of their parents (the
+// sources of their incoming edges, in whatever layer) to straighten edges.
+func assignCoordinates(g *Graph, layers [][]string, reversedEdges map[int]bool) *Layout {
+	x := map[string]float64{}
+	y := map[string]float64{}
+
+	maxWidth := 0.0
+	for _, layer := range layers {
+		w := float64(len(layer))*(nodeWidth+nodeGapX) - nodeGapX
+		if w > maxWidth {
+			maxWidth = w
+		}
+	}
+
+	for li, layer := range layers {
+		rowWidth := float64(len(layer))*(nodeWidth+nodeGapX) - nodeGapX
+		offset := (maxWidth - rowWidth) / 2
+		for ni, id := range layer {
+			x[id] = canvasPadX + offset + float64(ni)*(nodeWidth+nodeGapX)
+			y[id] = canvasPadY + float64(li)*(nodeHeight+layerGapY)
+		}
+	}
+
+	// median heuristic: nudge each node toward the median x of its parents
+	parents := map[string][]string{}
+	for _, e := range g.Edges {
+		parents[e.To] = append(parents[e.To], e.From)
+	}
+	for _, layer := range layers {
+		for _, id := range layer {
+			ps := parents[id]
+			if len(ps) == 0 {
+				continue
+			}
+			xs := make([]float64, len(ps))
+			for i, p := range ps {
+				xs[i] = x[p]
+			}
+			sort.Float64s(xs)
+			median := xs[len(xs)/2]
+			x[id] = (x[id] + median) / 2
+		}
+	}
+
+	out := &Layout{}
+	for _, id := range g.NodeOrder {
+		n := g.Nodes[id]
+		out.Nodes = append(out.Nodes, LayoutNode{
+			ID:    id,
+			Label: firstNonEmpty(n.Attrs.label(), id),
+			Shape: firstNonEmpty(n.Attrs["shape"], "box"),
+			Color: firstNonEmpty(n.Attrs["color"], "#4f46e5"),
+			X:     x[id],
+			Y:     y[id],
+			W:     nodeWidth,
+			H:     nodeHeight,
+		})
+	}
+
+	for i, e := range g.Edges {
+		out.Edges = append(out.Edges, LayoutEdge{
+			From:     e.From,
+			To:       e.To,
+			Color:    firstNonEmpty(e.Attrs["color"], "#1e1b4b"),
+			Label:    e.Attrs.label(),
+			Reversed: reversedEdges[i],
+			Points:   routeEdge(x[e.From], y[e.From], x[e.To], y[e.To]),
+		})
+	}
+
+	out.Width = maxWidth + 2*canvasPadX
+	out.Height = float64(len(layers))*(nodeHeight+layerGapY) - layerGapY + 2*canvasPadY
+	if out.Width < nodeWidth+2*canvasPadX {
+		out.Width = nodeWidth + 2*canvasPadX
+	}
+	return out
+}
+
+// routeEdge returns an orthogonal polyline from the bottom-center of the
+// source node to the top-center of the target node: down, across at
+// mid-height, then down again (two bends), falling back to a straight
+// center-to-center line when the nodes sit on the same row.
+func routeEdge(x1, y1, x2, y2 float64) [][2]float64 {
+	sx, sy := x1+nodeWidth/2, y1+nodeHeight
+	tx, ty := x2+nodeWidth/2, y2
+	if sy == ty {
+		return [][2]float64{{sx, y1 + nodeHeight/2}, {tx, y2 + nodeHeight/2}}
+	}
+	midY := sy + (ty-sy)/2
+	return [][2]float64{{sx, sy}, {sx, midY}, {tx, midY}, {tx, ty}}
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// RenderSVG emits a laid-out diagram as an SVG document, drawing each node
+// as a rounded rect (or ellipse for shape=ellipse/circle) and each edge as
+// a routed polyline with an arrowhead marker.
+func RenderSVG(l *Layout) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8"?>`+"\n")
+	fmt.Fprintf(&b, `<svg width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" xmlns="http://www.w3.org/2000/svg">`+"\n",
+		l.Width, l.Height, l.Width, l.Height)
+	b.WriteString(`<defs><marker id="arrow" markerWidth="10" markerHeight="10" refX="8" refY="3" orient="auto"><path d="M0,0 L8,3 L0,6 Z" fill="#1e1b4b"/></marker></defs>` + "\n")
+
+	for _, e := range l.Edges {
+		writePolyline(&b, e)
+	}
+	for _, n := range l.Nodes {
+		writeNode(&b, n)
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+func writeNode(b *strings.Builder, n LayoutNode) {
+	switch n.Shape {
+	case "ellipse", "circle":
+		cx, cy := n.X+n.W/2, n.Y+n.H/2
+		fmt.Fprintf(b, `<ellipse cx="%.1f" cy="%.1f" rx="%.1f" ry="%.1f" fill="%s" stroke="#1e1b4b" stroke-width="2" />`+"\n",
+			cx, cy, n.W/2, n.H/2, html.EscapeString(n.Color))
+	default:
+		fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="6" fill="%s" stroke="#1e1b4b" stroke-width="2" />`+"\n",
+			n.X, n.Y, n.W, n.H, html.EscapeString(n.Color))
+	}
+	fmt.Fprintf(b, `<text x="%.1f" y="%.1f" font-size="12" fill="#fff" text-anchor="middle" dominant-baseline="middle">%s</text>`+"\n",
+		n.X+n.W/2, n.Y+n.H/2, html.EscapeString(n.Label))
+}
+
+func writePolyline(b *strings.Builder, e LayoutEdge) {
+	pts := e.Points
+	if e.Reversed {
+		for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+			pts[i], pts[j] = pts[j], pts[i]
+		}
+	}
+	var coords []string
+	for _, p := range pts {
+		coords = append(coords, fmt.Sprintf("%.1f,%.1f", p[0], p[1]))
+	}
+	fmt.Fprintf(b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="1.5" marker-end="url(#arrow)" />`+"\n",
+		strings.Join(coords, " "), html.EscapeString(e.Color))
+	if e.Label != "" {
+		mid := pts[len(pts)/2]
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" font-size="10" fill="#374151" text-anchor="middle">%s</text>`+"\n",
+			mid[0], mid[1]-4, html.EscapeString(e.Label))
+	}
+}
@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"context"
+	"strings"
+
+	"github.com/archviz/backend-go/agent/render"
+)
+
+// EventType identifies a stage in a streamed diagram generation.
+type EventType string
+
+const (
+	EventPlan      EventType = "plan"       // image ID assigned, generation about to start
+	EventIRPartial EventType = "ir_partial" // DOT IR computed or loaded
+	EventLayout    EventType = "layout"     // node positions computed from the IR
+	EventSVGFrame  EventType = "svg_frame"  // SVG produced from the laid-out IR
+	EventDone      EventType = "done"       // SVG saved, result ready
+	EventError     EventType = "error"      // generation failed
+)
+
+// LayoutNode is one node's position and size, as placed by
+// render.ComputeLayout, for a client to render a progressive layout
+// preview before the final SVG frame arrives.
+type LayoutNode struct {
+	ID    string  `json:"id"`
+	Label string  `json:"label"`
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	W     float64 `json:"w"`
+	H     float64 `json:"h"`
+}
+
+// Layout is the node-position snapshot carried by an EventLayout.
+type Layout struct {
+	Width  float64      `json:"width"`
+	Height float64      `json:"height"`
+	Nodes  []LayoutNode `json:"nodes"`
+}
+
+// Event is one stage notification emitted by GenerateStream. Fields are
+// populated as they become relevant to the stage: DOT on EventIRPartial,
+// Layout on EventLayout, SVG on EventSVGFrame, Result on EventDone, Error
+// on EventError.
+type Event struct {
+	Type    EventType              `json:"type"`
+	ImageID string                 `json:"image_id,omitempty"`
+	DOT     string                 `json:"dot,omitempty"`
+	Layout  *Layout                `json:"layout,omitempty"`
+	SVG     string                 `json:"svg,omitempty"`
+	Result  map[string]interface{} `json:"result,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+// GenerateRequest parameterizes GenerateStream the same way Generate's
+// diagramType argument does.
+type GenerateRequest struct {
+	DiagramType string
+}
+
+// Generate produces a diagram and returns only its final result, for
+// callers that don't need progress updates.
+func (a *SimpleAgent) Generate(imageType string) (map[string]interface{}, error) {
+	return a.GenerateStream(context.Background(), GenerateRequest{DiagramType: imageType}, func(Event) {})
+}
+
+// GenerateStream is Generate's incremental form: it emits one Event per
+// stage (plan, IR partial, layout, SVG frame, done) via emit as it goes,
+// for callers streaming progress to a client over SSE or WebSocket. It
+// still returns the same final result map Generate does, or an error -
+// emit additionally receives an EventError when rendering or saving
+// fails. A canceled ctx returns ctx.Err() without an EventError.
+//
+// ctx is checked between stages so a client disconnecting mid-stream
+// (ctx canceled) stops work instead of finishing a generation nobody is
+// listening to anymore.
+func (a *SimpleAgent) GenerateStream(ctx context.Context, req GenerateRequest, emit func(Event)) (map[string]interface{}, error) {
+	id := nextImageID()
+	emit(Event{Type: EventPlan, ImageID: id})
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	dot := a.GetIR(id)["ir"].(string)
+	emit(Event{Type: EventIRPartial, ImageID: id, DOT: dot})
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// Layout is best-effort: hand-written IR a user pasted in may not
+	// parse, in which case RenderDOT below falls back to a static
+	// placeholder SVG anyway, so there's simply no layout preview to emit.
+	if g, err := render.ParseDOT(dot); err == nil {
+		emit(Event{Type: EventLayout, ImageID: id, Layout: toLayout(render.ComputeLayout(g))})
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	svg, err := a.RenderDOT(dot)
+	if err != nil {
+		emit(Event{Type: EventError, ImageID: id, Error: err.Error()})
+		return nil, err
+	}
+	emit(Event{Type: EventSVGFrame, ImageID: id, SVG: svg})
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	path, err := a.SaveSVG(id, req.DiagramType, svg)
+	if err != nil {
+		emit(Event{Type: EventError, ImageID: id, Error: err.Error()})
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"image_id":      id,
+		"image_version": 1,
+		"file_path":     strings.TrimPrefix(path, a.cfg.RootDir),
+		"svg":           svg,
+	}
+	emit(Event{Type: EventDone, ImageID: id, Result: result})
+	return result, nil
+}
+
+// toLayout narrows a render.Layout down to the position data an
+// EventLayout needs, dropping shape/color (the final svg_frame carries
+// those once rendering runs).
+func toLayout(l *render.Layout) *Layout {
+	nodes := make([]LayoutNode, len(l.Nodes))
+	for i, n := range l.Nodes {
+		nodes[i] = LayoutNode{ID: n.ID, Label: n.Label, X: n.X, Y: n.Y, W: n.W, H: n.H}
+	}
+	return &Layout{Width: l.Width, Height: l.Height, Nodes: nodes}
+}
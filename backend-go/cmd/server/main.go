@@ -15,32 +15,120 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
-	"sync"
 	"syscall"
 	"time"
 
 	"github.com/archviz/backend-go/agent"
+	"github.com/archviz/backend-go/httpapi"
+	"github.com/archviz/backend-go/ingest"
+	"github.com/archviz/backend-go/jobs"
 	"github.com/archviz/backend-go/mcp"
+	"github.com/archviz/backend-go/store"
 )
 
-// In-memory session store for standalone mode (minimal compatible subset)
-type Session struct {
-	ID      string                 `json:"session_id"`
-	Title   string                 `json:"title"`
-	Messages []map[string]any      `json:"messages"`
-	Images  []map[string]any       `json:"images"`
-	Diagrams []map[string]any      `json:"diagrams"`
-	Plans   []map[string]any       `json:"plans"`
-	SourceRepo *string             `json:"source_repo"`
-	SourceCommit *string           `json:"source_commit"`
-}
-
-var (
-	sessionsMu sync.Mutex
-	sessions = map[string]*Session{}
-	jobsMu sync.Mutex
-	jobs = map[string]map[string]any{}
-)
+// Session is an alias for the persisted session shape, kept so the rest
+// of this file (which predates the store package) doesn't need a
+// wholesale rename.
+type Session = store.Session
+
+// newJobSystem wires a Queue and worker Pool with the handlers this
+// backend currently knows how to run in the background: repo ingestion,
+// diagram render, and GIF export. The queue backend is selected the same
+// way store.Open picks a session backend, via cfg.JobsBackend.
+func newJobSystem(cfg config, ag *agent.SimpleAgent, st store.Store) (jobs.Queue, *jobs.Pool, error) {
+	queue, err := jobs.Open(cfg.JobsBackend, cfg.JobsBoltPath, 128)
+	if err != nil {
+		return nil, nil, err
+	}
+	pool := jobs.NewPool(queue, 4)
+	pool.RegisterHandler("ingest", ingestJobHandler(ag, st))
+	pool.RegisterHandler("render", renderJobHandler(ag))
+	pool.RegisterHandler("export_gif", exportGifJobHandler(ag))
+	pool.SetRecorder(storeRecorder{st})
+	return queue, pool, nil
+}
+
+// storeRecorder adapts store.Store to jobs.Recorder, so job history
+// survives a restart (queryable via Store.ListJobsByStatus) even when the
+// live scheduling queue is the non-durable MemoryQueue.
+type storeRecorder struct {
+	st store.Store
+}
+
+func (r storeRecorder) PutJob(ctx context.Context, job *jobs.Job) error {
+	return r.st.PutJob(ctx, &store.JobRecord{
+		ID:        job.ID,
+		Kind:      job.Kind,
+		Payload:   job.Payload,
+		Status:    string(job.Status),
+		Attempts:  job.Attempts,
+		Result:    job.Result,
+		Error:     job.Error,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.UpdatedAt,
+	})
+}
+
+// ingestJobHandler clones and analyzes the repo named in the job payload's
+// source_repo/source_commit fields, storing the resulting diagrams via ag,
+// and - if the job originated from a session's ingest subroute - attaches
+// them to that session's image list.
+func ingestJobHandler(ag *agent.SimpleAgent, st store.Store) jobs.HandlerFunc {
+	return func(ctx context.Context, job *jobs.Job) (map[string]any, error) {
+		repoURL, _ := job.Payload["source_repo"].(string)
+		if repoURL == "" {
+			return nil, fmt.Errorf("ingest job %s: missing source_repo", job.ID)
+		}
+		commit, _ := job.Payload["source_commit"].(string)
+
+		out, err := ingest.Run(ingest.Input{SourceRepo: repoURL, SourceCommit: commit}, ag)
+		if err != nil {
+			return nil, err
+		}
+
+		if sidRaw, ok := job.Payload["session_id"].(string); ok && sidRaw != "" {
+			for _, id := range out.Diagrams {
+				image := map[string]any{"id": id, "version": 1, "file_path": "", "title": "Ingested Diagram"}
+				_ = st.AppendImage(ctx, sidRaw, image)
+			}
+		}
+		diagrams := make([]any, len(out.Diagrams))
+		for i, id := range out.Diagrams {
+			diagrams[i] = id
+		}
+		return map[string]any{"diagrams": diagrams}, nil
+	}
+}
+
+func renderJobHandler(ag *agent.SimpleAgent) jobs.HandlerFunc {
+	return func(ctx context.Context, job *jobs.Job) (map[string]any, error) {
+		diagramType, _ := job.Payload["diagram_type"].(string)
+		if diagramType == "" {
+			diagramType = "diagram"
+		}
+		return ag.Generate(diagramType)
+	}
+}
+
+func exportGifJobHandler(ag *agent.SimpleAgent) jobs.HandlerFunc {
+	return func(ctx context.Context, job *jobs.Job) (map[string]any, error) {
+		id, _ := job.Payload["image_id"].(string)
+		diagramType, _ := job.Payload["diagram_type"].(string)
+		fromDOT, _ := job.Payload["from_ir"].(string)
+		toDOT, _ := job.Payload["to_ir"].(string)
+		if diagramType == "" {
+			diagramType = "component"
+		}
+		if fromDOT == "" {
+			fromDOT = "digraph empty {}"
+		}
+		path, err := ag.ExportGIF(id, diagramType, fromDOT, toDOT, agent.GIFOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"file_path": path}, nil
+	}
+}
 
 type config struct {
 	Port            int
@@ -49,6 +137,11 @@ type config struct {
 	PyPort          int
 	PyBin           string
 	RootDir         string
+	StoreBackend    string
+	StoreBoltPath   string
+	StoreDSN        string
+	JobsBackend     string
+	JobsBoltPath    string
 }
 
 func main() {
@@ -57,10 +150,15 @@ func main() {
 	if cfg.StartEmbeddedPy {
 		mode = "proxy"
 	}
-	log.Printf("msg=starting_go_backend mode=%s port=%d upstream=%s start_embedded_python=%t", mode, cfg.Port, cfg.UpstreamURL, cfg.StartEmbeddedPy)
+	log.Printf("msg=starting_go_backend mode=%s port=%d upstream=%s start_embedded_python=%t store_backend=%s", mode, cfg.Port, cfg.UpstreamURL, cfg.StartEmbeddedPy, cfg.StoreBackend)
+
+	st, err := store.Open(cfg.StoreBackend, cfg.StoreBoltPath, cfg.StoreDSN)
+	if err != nil {
+		log.Fatalf("msg=failed_to_open_store err=%v", err)
+	}
+	defer st.Close()
 
 	var pyCmd *exec.Cmd
-	var err error
 	if cfg.StartEmbeddedPy {
 		pyCmd, err = startEmbeddedPython(cfg)
 		if err != nil {
@@ -91,62 +189,31 @@ func main() {
 
 		// initialize standalone agent and MCP handlers
 		ag := agent.New(agent.AgentConfig{RootDir: cfg.RootDir})
-		mcpHandlers := mcp.NewHandlers(ag)
-
-		// Register MCP tool routes to be handled by Go directly (standalone)
-		mux.HandleFunc("/mcp/tool/generate", mcpHandlers.Generate)
-		mux.HandleFunc("/mcp/tool/feedback", mcpHandlers.Feedback)
-		mux.HandleFunc("/mcp/tool/ir/", mcpHandlers.IR)
-		mux.HandleFunc("/mcp/tool/export/svg/", mcpHandlers.ExportSVG)
-		mux.HandleFunc("/mcp/tool/export/gif/", func(w http.ResponseWriter, r *http.Request) {
-			http.Error(w, "gif export not implemented", http.StatusNotImplemented)
-		})
-
-		// session endpoints used by the UI
-		mux.HandleFunc("/api/sessions", createSessionHandler)
-		mux.HandleFunc("/api/sessions/", sessionDispatcher)
-
-		// API endpoint used by the UI to fetch rendered SVGs
-		mux.HandleFunc("/api/diagram/render", func(w http.ResponseWriter, r *http.Request) {
-			q := r.URL.Query()
-			imageId := q.Get("image_id")
-			if imageId == "" {
-				http.Error(w, "missing image_id", http.StatusBadRequest)
-				return
-			}
-			outputsDir := filepath.Join(cfg.RootDir, "outputs")
-			var found string
-			files, _ := os.ReadDir(outputsDir)
-			for _, f := range files {
-				if strings.HasPrefix(f.Name(), imageId+"_") && strings.HasSuffix(f.Name(), ".svg") {
-					found = filepath.Join(outputsDir, f.Name())
-					break
-				}
-			}
-			var svg string
-			if found != "" {
-				b, err := os.ReadFile(found)
-				if err == nil {
-					svg = string(b)
-				}
-			}
-			if svg == "" {
-				svg = agent.GenerateSVG(imageId, "component")
-			}
-			w.Header().Set("Content-Type", "application/json")
-			_ = json.NewEncoder(w).Encode(map[string]string{"svg": svg})
-		})
-
-			// Health endpoint for standalone mode
-			mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-				w.Header().Set("Content-Type", "application/json")
-				_, _ = w.Write([]byte(`{"status":"ok"}`))
-			})
-
-		// Health endpoint used by tests/monitoring
-		mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Content-Type", "application/json")
-			_, _ = w.Write([]byte(`{"status":"ok"}`))
+		mcpHandlers := mcp.NewHandlers(ag, st)
+		jobQueue, jobPool, err := newJobSystem(cfg, ag, st)
+		if err != nil {
+			log.Fatalf("msg=failed_to_open_job_queue err=%v", err)
+		}
+		jobsCtx, stopJobs := context.WithCancel(context.Background())
+		defer stopJobs()
+		jobPool.Start(jobsCtx)
+
+		httpapi.Register(mux, httpapi.Deps{
+			MCPGenerate:       mcpHandlers.Generate,
+			MCPGenerateStream: mcpHandlers.GenerateStream,
+			MCPFeedback:       mcpHandlers.Feedback,
+			MCPIR:             mcpHandlers.IR,
+			MCPExportSVG:      mcpHandlers.ExportSVG,
+			MCPExportGIF:      mcpHandlers.ExportGIF,
+			WSGenerate:        mcpHandlers.GenerateWS,
+			CreateSession:     createSessionHandler(st),
+			SessionDispatch:   sessionDispatcher(st, jobQueue),
+			CreateIngest:      createIngestHandler(jobQueue),
+			IngestStatus:      ingestStatusHandler(jobQueue),
+			JobEvents:         jobEventsHandler(jobQueue),
+			DiagramRender:     diagramRenderHandler(cfg),
+			Health:            healthHandler,
+			RateLimiter:       httpapi.NewRateLimiter(20, 40),
 		})
 
 		// Fallback: proxy everything else to upstream
@@ -187,54 +254,31 @@ func main() {
 
 	// initialize standalone agent and MCP handlers
 	ag := agent.New(agent.AgentConfig{RootDir: cfg.RootDir})
-	mcpHandlers := mcp.NewHandlers(ag)
-
-	// Register MCP tool routes to be handled by Go directly (standalone)
-	mux.HandleFunc("/mcp/tool/generate", mcpHandlers.Generate)
-	mux.HandleFunc("/mcp/tool/feedback", mcpHandlers.Feedback)
-	mux.HandleFunc("/mcp/tool/ir/", mcpHandlers.IR)
-	mux.HandleFunc("/mcp/tool/export/svg/", mcpHandlers.ExportSVG)
-	mux.HandleFunc("/mcp/tool/export/gif/", func(w http.ResponseWriter, r *http.Request) {
-		http.Error(w, "gif export not implemented", http.StatusNotImplemented)
-	})
-
-	// session endpoints used by the UI
-	mux.HandleFunc("/api/sessions", createSessionHandler)
-	mux.HandleFunc("/api/sessions/", sessionDispatcher)
-
-		// ingestion endpoints (global repo ingestion)
-		mux.HandleFunc("/api/ingest", createIngestHandler)
-		mux.HandleFunc("/api/ingest/", ingestStatusHandler)
-
-	// API endpoint used by the UI to fetch rendered SVGs
-	mux.HandleFunc("/api/diagram/render", func(w http.ResponseWriter, r *http.Request) {
-		q := r.URL.Query()
-		imageId := q.Get("image_id")
-		if imageId == "" {
-			http.Error(w, "missing image_id", http.StatusBadRequest)
-			return
-		}
-		outputsDir := filepath.Join(cfg.RootDir, "outputs")
-		var found string
-		files, _ := os.ReadDir(outputsDir)
-		for _, f := range files {
-			if strings.HasPrefix(f.Name(), imageId+"_") && strings.HasSuffix(f.Name(), ".svg") {
-				found = filepath.Join(outputsDir, f.Name())
-				break
-			}
-		}
-		var svg string
-		if found != "" {
-			b, err := os.ReadFile(found)
-			if err == nil {
-				svg = string(b)
-			}
-		}
-		if svg == "" {
-			svg = agent.GenerateSVG(imageId, "component")
-		}
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(map[string]string{"svg": svg})
+	mcpHandlers := mcp.NewHandlers(ag, st)
+	jobQueue, jobPool, err := newJobSystem(cfg, ag, st)
+	if err != nil {
+		log.Fatalf("msg=failed_to_open_job_queue err=%v", err)
+	}
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	jobPool.Start(jobsCtx)
+
+	httpapi.Register(mux, httpapi.Deps{
+		MCPGenerate:       mcpHandlers.Generate,
+		MCPGenerateStream: mcpHandlers.GenerateStream,
+		MCPFeedback:       mcpHandlers.Feedback,
+		MCPIR:             mcpHandlers.IR,
+		MCPExportSVG:      mcpHandlers.ExportSVG,
+		MCPExportGIF:      mcpHandlers.ExportGIF,
+		WSGenerate:        mcpHandlers.GenerateWS,
+		CreateSession:     createSessionHandler(st),
+		SessionDispatch:   sessionDispatcher(st, jobQueue),
+		CreateIngest:      createIngestHandler(jobQueue),
+		IngestStatus:      ingestStatusHandler(jobQueue),
+		JobEvents:         jobEventsHandler(jobQueue),
+		DiagramRender:     diagramRenderHandler(cfg),
+		Health:            healthHandler,
+		RateLimiter:       httpapi.NewRateLimiter(20, 40),
 	})
 
 	// default fallback: return 404 for unknown routes (no upstream dependency)
@@ -274,6 +318,11 @@ func loadConfig() config {
 	upstream := getenvDefault("GO_BACKEND_UPSTREAM", fmt.Sprintf("http://127.0.0.1:%d", pyPort))
 	startEmbedded := getenvDefault("GO_START_EMBEDDED_PYTHON", "0") != "0"
 	pyBin := getenvDefault("PYTHON_BIN", defaultPythonBin(root))
+	storeBackend := getenvDefault("STORE_BACKEND", store.BackendMemory)
+	storeBoltPath := getenvDefault("STORE_BOLT_PATH", filepath.Join(root, "data", "sessions.db"))
+	storeDSN := getenvDefault("STORE_DSN", "")
+	jobsBackend := getenvDefault("JOBS_BACKEND", jobs.BackendMemory)
+	jobsBoltPath := getenvDefault("JOBS_BOLT_PATH", filepath.Join(root, "data", "jobs.db"))
 
 	return config{
 		Port:            port,
@@ -282,6 +331,11 @@ func loadConfig() config {
 		PyPort:          pyPort,
 		PyBin:           pyBin,
 		RootDir:         root,
+		StoreBackend:    storeBackend,
+		StoreBoltPath:   storeBoltPath,
+		StoreDSN:        storeDSN,
+		JobsBackend:     jobsBackend,
+		JobsBoltPath:    jobsBoltPath,
 	}
 }
 
@@ -442,14 +496,10 @@ func exportSvgHandler(cfg config) http.HandlerFunc {
 	}
 }
 
-func exportGifHandler(cfg config) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// For simplicity return a 501 until a proper GIF renderer is implemented
-		http.Error(w, "gif export not implemented", http.StatusNotImplemented)
-	}
-}
-
-func apiDiagramRenderHandler(cfg config) http.HandlerFunc {
+// diagramRenderHandler serves the API endpoint used by the UI to fetch a
+// previously rendered SVG by image_id, falling back to a placeholder if
+// no output file is found.
+func diagramRenderHandler(cfg config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		q := r.URL.Query()
 		imageId := q.Get("image_id")
@@ -483,154 +533,241 @@ func apiDiagramRenderHandler(cfg config) http.HandlerFunc {
 	}
 }
 
-// --- minimal session handlers ---
-func createSessionHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	id := generateID()
-	s := &Session{
-		ID: id,
-		Title: fmt.Sprintf("Session %s", id),
-		Messages: []map[string]any{},
-		Images: []map[string]any{},
-		Diagrams: []map[string]any{},
-		Plans: []map[string]any{},
-	}
-	sessionsMu.Lock()
-	sessions[id] = s
-	sessionsMu.Unlock()
+func healthHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(map[string]string{"session_id": id})
+	_, _ = w.Write([]byte(`{"status":"ok"}`))
 }
 
-func sessionDispatcher(w http.ResponseWriter, r *http.Request) {
-	// path: /api/sessions/{id} or /api/sessions/{id}/messages or /api/sessions/{id}/ingest
-	p := strings.TrimPrefix(r.URL.Path, "/api/sessions/")
-	if p == "" {
-		http.NotFound(w, r)
-		return
+// --- minimal session handlers ---
+func createSessionHandler(st store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		id := generateID()
+		s := &Session{
+			ID:       id,
+			Title:    fmt.Sprintf("Session %s", id),
+			Messages: []map[string]any{},
+			Images:   []map[string]any{},
+			Diagrams: []map[string]any{},
+			Plans:    []map[string]any{},
+		}
+		if err := st.CreateSession(r.Context(), s); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"session_id": id})
 	}
-	parts := strings.SplitN(p, "/", 2)
-	id := parts[0]
-	sub := ""
-	if len(parts) == 2 {
-		sub = parts[1]
+}
+
+func sessionDispatcher(st store.Store, queue jobs.Queue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// path: .../sessions/{id} or .../sessions/{id}/messages or .../sessions/{id}/ingest
+		p := pathAfter(r.URL.Path, "sessions")
+		if p == "" {
+			http.NotFound(w, r)
+			return
+		}
+		parts := strings.SplitN(p, "/", 2)
+		id := parts[0]
+		sub := ""
+		if len(parts) == 2 {
+			sub = parts[1]
+		}
+
+		s, ok, err := st.GetSession(r.Context(), id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+
+		switch {
+		case sub == "" && r.Method == http.MethodGet:
+			// return session detail
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"session_id": s.ID,
+				"title": s.Title,
+				"messages": s.Messages,
+				"images": s.Images,
+				"diagrams": s.Diagrams,
+				"plans": s.Plans,
+				"source_repo": s.SourceRepo,
+				"source_commit": s.SourceCommit,
+			})
+			return
+		case sub == "messages" && r.Method == http.MethodPost:
+			var payload map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			msg := map[string]any{"id": generateID(), "content": payload["content"]}
+			if err := st.AppendMessage(r.Context(), id, msg); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(msg)
+			return
+		case sub == "ingest" && (r.Method == http.MethodPost || r.Method == http.MethodPut):
+			var payload map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			if payload == nil {
+				payload = map[string]any{}
+			}
+			payload["session_id"] = id
+			job := newJob("ingest", payload)
+			if err := queue.Enqueue(r.Context(), job); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			_ = json.NewEncoder(w).Encode(job)
+			return
+		default:
+			http.Error(w, "not implemented", http.StatusNotFound)
+			return
+		}
 	}
+}
 
-	sessionsMu.Lock()
-	s, ok := sessions[id]
-	sessionsMu.Unlock()
-	if !ok {
-		http.Error(w, "session not found", http.StatusNotFound)
-		return
+// pathAfter returns the remainder of path following the last "/marker/"
+// segment. Handlers registered through httpapi.Register answer on both a
+// canonical "/api/v1/<marker>/..." path and a deprecated "/api/<marker>/..."
+// alias, so a fixed TrimPrefix of one or the other silently breaks on
+// whichever path it wasn't written for - this works for either.
+func pathAfter(path, marker string) string {
+	needle := "/" + marker + "/"
+	idx := strings.LastIndex(path, needle)
+	if idx == -1 {
+		return ""
+	}
+	return path[idx+len(needle):]
+}
+
+// newJob builds a freshly queued Job; callers hand it straight to a Queue.
+func newJob(kind string, payload map[string]any) *jobs.Job {
+	now := time.Now()
+	return &jobs.Job{
+		ID:        generateID(),
+		Kind:      kind,
+		Payload:   payload,
+		Status:    jobs.StatusQueued,
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
+}
 
-	switch {
-	case sub == "" && r.Method == http.MethodGet:
-		// return session detail
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(map[string]any{
-			"session_id": s.ID,
-			"title": s.Title,
-			"messages": s.Messages,
-			"images": s.Images,
-			"diagrams": s.Diagrams,
-			"plans": s.Plans,
-			"source_repo": s.SourceRepo,
-			"source_commit": s.SourceCommit,
-		})
-		return
-	case sub == "messages" && r.Method == http.MethodPost:
+// --- ingest job handlers, backed by the jobs package ---
+func createIngestHandler(queue jobs.Queue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
 		var payload map[string]any
 		_ = json.NewDecoder(r.Body).Decode(&payload)
-		msg := map[string]any{"id": generateID(), "content": payload["content"]}
-		sessionsMu.Lock()
-		s.Messages = append(s.Messages, msg)
-		sessionsMu.Unlock()
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(msg)
-		return
-	case sub == "ingest" && (r.Method == http.MethodPost || r.Method == http.MethodPut):
-		// minimal stub: accept and return a job id
-		jobId := generateID()
+		job := newJob("ingest", payload)
+		if err := queue.Enqueue(r.Context(), job); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(map[string]any{"job_id": jobId, "status": "queued"})
-		return
-	default:
-		http.Error(w, "not implemented", http.StatusNotFound)
-		return
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(job)
 	}
 }
 
-// --- minimal ingest job handlers ---
-func createIngestHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
+func ingestStatusHandler(queue jobs.Queue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// GET .../ingest/{jobId}
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		id := pathAfter(r.URL.Path, "ingest")
+		if id == "" {
+			http.Error(w, "missing id", http.StatusBadRequest)
+			return
+		}
+		job, ok, err := queue.Get(r.Context(), id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			http.Error(w, "job not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(job)
 	}
-	var payload map[string]any
-	_ = json.NewDecoder(r.Body).Decode(&payload)
-	jobId := generateID()
-	job := map[string]any{
-		"job_id": jobId,
-		"status": "queued",
-		"result": nil,
-		"error": nil,
-	}
-	jobsMu.Lock()
-	jobs[jobId] = job
-	jobsMu.Unlock()
-
-	// simulate background ingestion: complete after short delay
-	go func(jid string, p map[string]any) {
-		time.Sleep(1500 * time.Millisecond)
-		jobsMu.Lock()
-		if j, ok := jobs[jid]; ok {
-			j["status"] = "complete"
-			j["result"] = map[string]any{"diagrams": []any{}}
-			jobs[jid] = j
-		}
-		jobsMu.Unlock()
-
-		// if session_id provided, add a placeholder image to session
-		if sidRaw, ok := p["session_id"].(string); ok && sidRaw != "" {
-			sessionsMu.Lock()
-			if s, ok := sessions[sidRaw]; ok {
-				img := map[string]any{"id": generateID(), "version": 1, "file_path": "", "title": "Ingested Diagram"}
-				s.Images = append(s.Images, img)
-				sessions[sidRaw] = s
+}
+
+// jobEventsHandler serves GET /api/jobs/{id}/events as Server-Sent Events,
+// emitting the job's current status immediately and then one more event
+// per status transition until it reaches a terminal state.
+func jobEventsHandler(queue jobs.Queue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(pathAfter(r.URL.Path, "jobs"), "/events")
+		if id == "" || !strings.HasSuffix(r.URL.Path, "/events") {
+			http.NotFound(w, r)
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+
+		// Subscribe before reading the current state so a transition that
+		// lands between the two isn't lost.
+		updates, unsubscribe := queue.Subscribe(id)
+		defer unsubscribe()
+
+		if job, ok, _ := queue.Get(r.Context(), id); ok {
+			writeJobEvent(w, job)
+			flusher.Flush()
+			if job.Status == jobs.StatusDone || job.Status == jobs.StatusFailed {
+				return
 			}
-			sessionsMu.Unlock()
 		}
-	}(jobId, payload)
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusAccepted)
-	_ = json.NewEncoder(w).Encode(job)
+		for {
+			select {
+			case job, ok := <-updates:
+				if !ok {
+					return
+				}
+				writeJobEvent(w, job)
+				flusher.Flush()
+				if job.Status == jobs.StatusDone || job.Status == jobs.StatusFailed {
+					return
+				}
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
 }
 
-func ingestStatusHandler(w http.ResponseWriter, r *http.Request) {
-	// GET /api/ingest/{jobId}
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	id := strings.TrimPrefix(r.URL.Path, "/api/ingest/")
-	if id == "" {
-		http.Error(w, "missing id", http.StatusBadRequest)
-		return
-	}
-	jobsMu.Lock()
-	job, ok := jobs[id]
-	jobsMu.Unlock()
-	if !ok {
-		http.Error(w, "job not found", http.StatusNotFound)
+func writeJobEvent(w http.ResponseWriter, job *jobs.Job) {
+	data, err := json.Marshal(job)
+	if err != nil {
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(job)
+	fmt.Fprintf(w, "event: status\ndata: %s\n\n", data)
 }
 
 func generateID() string {
@@ -0,0 +1,231 @@
+package httpapi
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Middleware wraps a handler to add cross-cutting behavior (logging,
+// recovery, etc.). Middlewares are applied outermost-first: Chain(h, A, B)
+// runs A, then B, then h.
+type Middleware func(http.Handler) http.Handler
+
+// Chain applies mw to h in order, so the first middleware in the list is
+// the outermost wrapper and runs first on the way in.
+func Chain(h http.Handler, mw ...Middleware) http.Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
+
+// statusWriter records the status code written so Logging can report it.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Flush lets streaming handlers (SSE) keep using http.Flusher through the
+// wrapper.
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack lets WebSocket handlers take over the connection through the
+// wrapper, as net/http.Hijacker requires.
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("httpapi: underlying ResponseWriter does not support hijacking")
+	}
+	return h.Hijack()
+}
+
+// Logging logs one line per request: method, path, status and duration.
+func Logging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+		log.Printf("msg=http_request method=%s path=%s status=%d duration_ms=%d",
+			r.Method, r.URL.Path, sw.status, time.Since(start).Milliseconds())
+	})
+}
+
+// Recovery turns a panic anywhere downstream into a 500 instead of
+// crashing the server.
+func Recovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Printf("msg=http_panic_recovered path=%s err=%v", r.URL.Path, rec)
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CORS allows cross-origin requests from any origin, reflecting the
+// request's Origin header and answering preflight OPTIONS requests
+// directly.
+func CORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			origin = "*"
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// gzipWriter wraps an http.ResponseWriter so Write transparently compresses.
+type gzipWriter struct {
+	http.ResponseWriter
+	gz *gzip.Writer
+}
+
+func (w *gzipWriter) Write(b []byte) (int, error) {
+	return w.gz.Write(b)
+}
+
+// Gzip compresses the response body when the client advertises gzip
+// support. It is not applied to streaming (SSE) routes - see
+// buildChain in router.go.
+func Gzip(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Del("Content-Length")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		next.ServeHTTP(&gzipWriter{ResponseWriter: w, gz: gz}, r)
+	})
+}
+
+// RateLimiter is a simple per-client token bucket, keyed by remote IP.
+type RateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	rate    float64 // tokens added per second
+	burst   float64 // bucket capacity
+}
+
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// NewRateLimiter creates a limiter allowing ratePerSecond sustained
+// requests per client with bursts up to burst.
+func NewRateLimiter(ratePerSecond, burst float64) *RateLimiter {
+	return &RateLimiter{buckets: map[string]*bucket{}, rate: ratePerSecond, burst: burst}
+}
+
+func (l *RateLimiter) allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, lastSeen: now}
+		l.buckets[key] = b
+	}
+	elapsed := now.Sub(b.lastSeen).Seconds()
+	b.lastSeen = now
+	b.tokens += elapsed * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Middleware returns a Middleware that rejects requests over the limit
+// with 429 Too Many Requests.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.allow(clientKey(r)) {
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func clientKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// requestTimeoutHeader lets a caller request a tighter deadline than a
+// route's default - e.g. a UI that wants to give up and show its own
+// timeout error sooner than the server otherwise would.
+const requestTimeoutHeader = "X-Request-Timeout"
+
+// Deadline bounds request handling to d - or to whatever shorter duration
+// the client requests via the X-Request-Timeout header (seconds) - by
+// attaching a context deadline. It does not itself cancel the response -
+// handlers are expected to respect r.Context().Done(), as the job and
+// ingest handlers already do.
+func Deadline(d time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), requestTimeout(r, d))
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// requestTimeout resolves the deadline to use for r: the route's default
+// d, tightened to the client's X-Request-Timeout value (a positive number
+// of seconds) if one is given and it's no longer than d. Missing,
+// unparseable, non-positive, or longer-than-d values fall back to d - the
+// header can only shorten a route's deadline, never extend it.
+func requestTimeout(r *http.Request, d time.Duration) time.Duration {
+	v := r.Header.Get(requestTimeoutHeader)
+	if v == "" {
+		return d
+	}
+	secs, err := strconv.ParseFloat(v, 64)
+	if err != nil || secs <= 0 {
+		return d
+	}
+	if requested := time.Duration(secs * float64(time.Second)); requested < d {
+		return requested
+	}
+	return d
+}
@@ -0,0 +1,140 @@
+// Package httpapi assembles this backend's HTTP surface: a versioned
+// /api/v1/... route table (with deprecated unversioned aliases kept for
+// existing clients) plus a shared middleware chain for logging, panic
+// recovery, CORS, gzip, rate-limiting and request deadlines. Register is
+// the single place new routes get wired in, replacing the ad-hoc mux
+// setup that used to be duplicated between main's proxy and standalone
+// modes.
+package httpapi
+
+import (
+	"net/http"
+	"time"
+)
+
+// Deps holds the handlers Register wires onto a mux. Each field is an
+// already-constructed handler; Register only owns routing, versioning
+// and middleware, not handler logic.
+type Deps struct {
+	MCPGenerate       http.HandlerFunc
+	MCPGenerateStream http.HandlerFunc
+	MCPFeedback       http.HandlerFunc
+	MCPIR             http.HandlerFunc
+	MCPExportSVG      http.HandlerFunc
+	MCPExportGIF      http.HandlerFunc
+
+	WSGenerate http.HandlerFunc
+
+	CreateSession   http.HandlerFunc
+	SessionDispatch http.HandlerFunc
+	CreateIngest    http.HandlerFunc
+	IngestStatus    http.HandlerFunc
+	JobEvents       http.HandlerFunc
+	DiagramRender   http.HandlerFunc
+
+	Health http.HandlerFunc
+
+	// RateLimiter is optional; when nil, no rate limiting is applied.
+	RateLimiter *RateLimiter
+}
+
+// route is one path registered under both its versioned canonical form
+// and (when legacy is set) a deprecated unversioned alias.
+type route struct {
+	path      string // e.g. "/sessions" - versioned as "/api/v1" + path
+	handler   http.HandlerFunc
+	streaming bool   // true for SSE/long-lived responses: skips gzip, 1h deadline instead of 30s
+	legacy    string // pre-versioning path this route also answers on, deprecated
+}
+
+// Register wires deps' handlers onto mux under /api/v1/..., plus their
+// pre-versioning paths as deprecated aliases, and the unversioned
+// /mcp/tool/... and /health routes this backend has always exposed
+// outside the /api namespace.
+func Register(mux *http.ServeMux, deps Deps) {
+	standard := buildChain(deps, false)
+	streaming := buildChain(deps, true)
+
+	// MCP tool routes are a separate, unversioned protocol surface - not
+	// part of the /api/v1 REST namespace.
+	mux.Handle("/mcp/tool/generate", standard(deps.MCPGenerate))
+	mux.Handle("/mcp/tool/feedback", standard(deps.MCPFeedback))
+	mux.Handle("/mcp/tool/ir/", standard(deps.MCPIR))
+	mux.Handle("/mcp/tool/export/svg/", standard(deps.MCPExportSVG))
+	mux.Handle("/mcp/tool/export/gif/", standard(deps.MCPExportGIF))
+	if deps.MCPGenerateStream != nil {
+		mux.Handle("/mcp/tool/generate/stream", streaming(deps.MCPGenerateStream))
+	}
+
+	// /ws/generate is a WebSocket upgrade, not a REST route - it gets the
+	// streaming chain too (no Gzip buffering, long deadline) so the
+	// underlying connection stays hijackable and open for the duration of
+	// the generation.
+	if deps.WSGenerate != nil {
+		mux.Handle("/ws/generate", streaming(deps.WSGenerate))
+	}
+
+	if deps.Health != nil {
+		mux.Handle("/health", standard(deps.Health))
+	}
+
+	routes := []route{
+		{path: "/sessions", handler: deps.CreateSession, legacy: "/api/sessions"},
+		{path: "/sessions/", handler: deps.SessionDispatch, legacy: "/api/sessions/"},
+		{path: "/ingest", handler: deps.CreateIngest, legacy: "/api/ingest"},
+		{path: "/ingest/", handler: deps.IngestStatus, legacy: "/api/ingest/"},
+		{path: "/jobs/", handler: deps.JobEvents, legacy: "/api/jobs/", streaming: true},
+		{path: "/diagram/render", handler: deps.DiagramRender, legacy: "/api/diagram/render"},
+	}
+
+	for _, rt := range routes {
+		if rt.handler == nil {
+			continue
+		}
+		chain := standard
+		if rt.streaming {
+			chain = streaming
+		}
+		mux.Handle("/api/v1"+rt.path, chain(rt.handler))
+		if rt.legacy != "" {
+			mux.Handle(rt.legacy, chain(deprecated(rt.handler)))
+		}
+	}
+}
+
+// deprecated marks responses from a pre-versioning alias route so clients
+// know to migrate to the /api/v1 path.
+func deprecated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "/api/v1"+trimAPIPrefix(r.URL.Path)+`; rel="successor-version"`)
+		next(w, r)
+	}
+}
+
+func trimAPIPrefix(path string) string {
+	const prefix = "/api"
+	if len(path) >= len(prefix) && path[:len(prefix)] == prefix {
+		return path[len(prefix):]
+	}
+	return path
+}
+
+// buildChain returns a function that wraps a handler with this backend's
+// standard middleware stack. Streaming routes skip Gzip (which buffers
+// compressed output) and use a long deadline so SSE connections aren't
+// cut off mid-stream.
+func buildChain(deps Deps, streaming bool) func(http.HandlerFunc) http.Handler {
+	return func(h http.HandlerFunc) http.Handler {
+		mw := []Middleware{Logging, Recovery, CORS}
+		if deps.RateLimiter != nil {
+			mw = append(mw, deps.RateLimiter.Middleware)
+		}
+		if streaming {
+			mw = append(mw, Deadline(1*time.Hour))
+		} else {
+			mw = append(mw, Gzip, Deadline(30*time.Second))
+		}
+		return Chain(h, mw...)
+	}
+}
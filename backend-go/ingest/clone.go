@@ -0,0 +1,153 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// cloneTimeout bounds how long a single clone may run, so a huge or
+// deliberately slow repo can't hang an ingest worker forever. The
+// revision lookup and checkout after the clone take no context and are
+// not bounded by it. When a specific source_commit is requested it's also
+// the only resource bound on history depth: a shallow clone would make
+// checking out an arbitrary source_commit fail whenever that commit isn't
+// the one shallow history happens to include.
+const cloneTimeout = 2 * time.Minute
+
+// cloneDepth limits a clone to its most recent history when no specific
+// commit was requested - Run only analyzes the working tree at the
+// default branch's tip in that case, so full history buys nothing.
+const cloneDepth = 1
+
+func init() {
+	// Route all https:// git traffic through a client whose DialContext
+	// resolves and validates the target IP itself, then dials that same
+	// address - see dialValidated. Without this, validateRepoURL's
+	// pre-flight resolution and go-git's own (later, independent) DNS
+	// lookup could be answered differently by a DNS-rebinding attacker,
+	// letting an allowed-looking host redirect the actual connection to a
+	// disallowed address.
+	client.InstallProtocol("https", githttp.NewClient(&http.Client{
+		Transport: &http.Transport{DialContext: dialValidated},
+	}))
+}
+
+// CloneAt clones repoURL into a fresh temp directory and, if commit is
+// non-empty, checks out that commit (SHA or ref). repoURL is validated by
+// validateRepoURL first: it comes straight from the /api/ingest request
+// body, so an unchecked clone would let a caller point this server at an
+// internal host or the local filesystem. The caller is responsible for
+// removing the returned directory once done with it.
+func CloneAt(repoURL, commit string) (string, error) {
+	if err := validateRepoURL(repoURL); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cloneTimeout)
+	defer cancel()
+
+	dir, err := os.MkdirTemp("", "ingest-clone-*")
+	if err != nil {
+		return "", fmt.Errorf("ingest: creating clone dir: %w", err)
+	}
+
+	opts := &git.CloneOptions{URL: repoURL}
+	if commit == "" {
+		// No specific commit requested: the default branch's tip is all
+		// Run needs, so keep the clone shallow.
+		opts.Depth = cloneDepth
+	}
+	repo, err := git.PlainCloneContext(ctx, dir, false, opts)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("ingest: cloning %s: %w", repoURL, err)
+	}
+
+	if commit == "" {
+		return dir, nil
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("ingest: opening worktree: %w", err)
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(commit))
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("ingest: resolving revision %s: %w", commit, err)
+	}
+	if err := wt.Checkout(&git.CheckoutOptions{Hash: *hash}); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("ingest: checking out %s: %w", commit, err)
+	}
+	return dir, nil
+}
+
+// validateRepoURL rejects clone targets that could be abused for SSRF or
+// local file access: only an https:// URL with a host is allowed. This is
+// a cheap, early rejection of obviously-wrong input; the address-level
+// check (no loopback/private/link-local destinations) happens once, at
+// actual connection time, in dialValidated - see its doc comment for why.
+func validateRepoURL(repoURL string) error {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return fmt.Errorf("ingest: invalid repo URL %q: %w", repoURL, err)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("ingest: repo URL %q must use https", repoURL)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("ingest: repo URL %q has no host", repoURL)
+	}
+	return nil
+}
+
+// dialValidated is the DialContext for every https:// connection go-git
+// makes (installed in init). It resolves addr's host exactly once and
+// dials whichever returned IP passes isDisallowedCloneIP, so the
+// connection actually used is the same one that was checked - validating
+// a hostname and then letting go-git (or net/http) resolve it again
+// independently would let a DNS-rebinding attacker answer the two lookups
+// differently and clone from a disallowed address anyway.
+func dialValidated(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("ingest: invalid dial address %q: %w", addr, err)
+	}
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("ingest: resolving repo host %q: %w", host, err)
+	}
+	var d net.Dialer
+	var lastErr error
+	for _, ipAddr := range ips {
+		if isDisallowedCloneIP(ipAddr.IP) {
+			continue
+		}
+		conn, err := d.DialContext(ctx, network, net.JoinHostPort(ipAddr.IP.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	if lastErr != nil {
+		return nil, fmt.Errorf("ingest: dialing repo host %q: %w", host, lastErr)
+	}
+	return nil, fmt.Errorf("ingest: repo host %q has no allowed address to dial", host)
+}
+
+func isDisallowedCloneIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+}
@@ -0,0 +1,161 @@
+package ingest
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ComponentNode is a deployable unit discovered from a docker-compose
+// service or a Kubernetes workload manifest.
+type ComponentNode struct {
+	Name  string
+	Kind  string // "compose-service", "k8s-deployment", "k8s-statefulset", etc.
+	Image string
+}
+
+// ComponentEdge links two ComponentNodes, e.g. a compose "depends_on"
+// relationship or a shared volume mount.
+type ComponentEdge struct {
+	From, To string
+	Kind     string // "depends_on", "volume"
+}
+
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+}
+
+type composeService struct {
+	Image      string      `yaml:"image"`
+	DependsOn  interface{} `yaml:"depends_on"`
+	Volumes    []string    `yaml:"volumes"`
+	VolumesTag []string    `yaml:"volumes_from"`
+}
+
+type k8sManifest struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Template struct {
+			Spec struct {
+				Containers []struct {
+					Image   string `yaml:"image"`
+					Volumes []struct {
+						Name string `yaml:"name"`
+					} `yaml:"volumeMounts"`
+				} `yaml:"containers"`
+			} `yaml:"spec"`
+		} `yaml:"template"`
+	} `yaml:"spec"`
+}
+
+// DetectDeploymentManifests walks root for docker-compose*.yml/.yaml files
+// and Kubernetes manifests (any YAML with a recognized `kind`), returning
+// the components they describe plus the depends_on/volume edges between
+// them.
+func DetectDeploymentManifests(root string) ([]ComponentNode, []ComponentEdge, error) {
+	var nodes []ComponentNode
+	var edges []ComponentEdge
+	volumeOwners := map[string][]string{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() {
+			if shouldSkipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isYAML(d.Name()) {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		if isComposeFile(d.Name()) {
+			var cf composeFile
+			if yaml.Unmarshal(data, &cf) != nil {
+				return nil
+			}
+			for name, svc := range cf.Services {
+				nodes = append(nodes, ComponentNode{Name: name, Kind: "compose-service", Image: svc.Image})
+				for _, dep := range composeDependsOn(svc.DependsOn) {
+					edges = append(edges, ComponentEdge{From: name, To: dep, Kind: "depends_on"})
+				}
+				for _, v := range svc.Volumes {
+					vol := strings.SplitN(v, ":", 2)[0]
+					volumeOwners[vol] = append(volumeOwners[vol], name)
+				}
+			}
+			return nil
+		}
+
+		var km k8sManifest
+		if yaml.Unmarshal(data, &km) != nil || km.Kind == "" {
+			return nil
+		}
+		switch km.Kind {
+		case "Deployment", "StatefulSet", "DaemonSet":
+			image := ""
+			if containers := km.Spec.Template.Spec.Containers; len(containers) > 0 {
+				image = containers[0].Image
+			}
+			nodes = append(nodes, ComponentNode{Name: km.Metadata.Name, Kind: "k8s-" + strings.ToLower(km.Kind), Image: image})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, owners := range volumeOwners {
+		for i := 0; i < len(owners); i++ {
+			for j := i + 1; j < len(owners); j++ {
+				edges = append(edges, ComponentEdge{From: owners[i], To: owners[j], Kind: "volume"})
+			}
+		}
+	}
+
+	return nodes, edges, nil
+}
+
+func isYAML(name string) bool {
+	return strings.HasSuffix(name, ".yml") || strings.HasSuffix(name, ".yaml")
+}
+
+func isComposeFile(name string) bool {
+	return strings.HasPrefix(name, "docker-compose")
+}
+
+// composeDependsOn normalizes depends_on, which compose allows as either a
+// list of service names or a map of service name to condition.
+func composeDependsOn(v interface{}) []string {
+	switch val := v.(type) {
+	case []interface{}:
+		out := make([]string, 0, len(val))
+		for _, item := range val {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	case map[string]interface{}:
+		out := make([]string, 0, len(val))
+		for k := range val {
+			out = append(out, k)
+		}
+		return out
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,93 @@
+package ingest
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// GoPackage is one importable directory of the analyzed repo.
+type GoPackage struct {
+	Dir     string // slash-separated path relative to the repo root ("." for the root package)
+	Name    string // package name as declared in its files
+	Imports []string
+}
+
+var moduleDirectiveRe = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+
+// readModulePath extracts the `module` directive from root/go.mod.
+func readModulePath(root string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("ingest: reading go.mod: %w", err)
+	}
+	m := moduleDirectiveRe.FindSubmatch(data)
+	if m == nil {
+		return "", fmt.Errorf("ingest: no module directive found in go.mod")
+	}
+	return string(m[1]), nil
+}
+
+// walkGoPackages parses the import declarations of every .go file under
+// root (skipping tests and vendored/build dirs) and groups them by
+// directory, one GoPackage per directory that contains Go source.
+//
+// This parses import declarations only (parser.ImportsOnly) rather than
+// doing a full type-checked load via go/packages: a type-checked load
+// would need to resolve the target repo's own module dependencies, which
+// isn't possible without network access to its module proxy.
+func walkGoPackages(root string) ([]GoPackage, error) {
+	byDir := map[string]*GoPackage{}
+	var order []string
+	fset := token.NewFileSet()
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() {
+			if shouldSkipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
+		if err != nil {
+			return nil // skip unparsable files rather than failing the whole ingest
+		}
+
+		rel, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		pkg, ok := byDir[rel]
+		if !ok {
+			pkg = &GoPackage{Dir: rel, Name: f.Name.Name}
+			byDir[rel] = pkg
+			order = append(order, rel)
+		}
+		for _, imp := range f.Imports {
+			pkg.Imports = append(pkg.Imports, strings.Trim(imp.Path.Value, `"`))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pkgs := make([]GoPackage, len(order))
+	for i, dir := range order {
+		pkgs[i] = *byDir[dir]
+	}
+	return pkgs, nil
+}
@@ -0,0 +1,82 @@
+// Package ingest turns a cloned Git repository into the normalized IR the
+// rest of this backend already knows how to render: it analyzes Go
+// package imports, language-specific service manifests, and container/k8s
+// deployment manifests, then emits one DOT graph per diagram type.
+package ingest
+
+import "os"
+
+// Input describes the repository Run should analyze.
+type Input struct {
+	SourceRepo   string
+	SourceCommit string
+}
+
+// Output lists the image IDs of the diagrams Run generated, one per
+// non-empty diagram type BuildIR produced.
+type Output struct {
+	Diagrams []string
+}
+
+// diagramStore is the subset of *agent.SimpleAgent Run needs, kept as an
+// interface so this package doesn't import agent directly and the two
+// packages can evolve independently.
+type diagramStore interface {
+	StoreGeneratedDiagram(diagramType, dot string) (string, error)
+}
+
+// Run clones in.SourceRepo at in.SourceCommit (or the default branch, if
+// empty), analyzes it, and stores the resulting component/package/
+// deployment diagrams via store. The clone is removed before Run returns.
+func Run(in Input, store diagramStore) (Output, error) {
+	dir, err := CloneAt(in.SourceRepo, in.SourceCommit)
+	if err != nil {
+		return Output{}, err
+	}
+	defer removeAll(dir)
+
+	modulePath, err := readModulePath(dir)
+	if err != nil {
+		modulePath = in.SourceRepo
+	}
+	pkgs, err := walkGoPackages(dir)
+	if err != nil {
+		return Output{}, err
+	}
+	services, err := DetectServiceManifests(dir)
+	if err != nil {
+		return Output{}, err
+	}
+	components, compEdges, err := DetectDeploymentManifests(dir)
+	if err != nil {
+		return Output{}, err
+	}
+
+	result := BuildIR(pkgs, modulePath, services, components, compEdges)
+
+	diagrams := []struct {
+		kind string
+		dot  string
+	}{
+		{"package", result.Package},
+		{"component", result.Component},
+		{"deployment", result.Deployment},
+	}
+
+	var out Output
+	for _, d := range diagrams {
+		if d.dot == "" {
+			continue
+		}
+		id, err := store.StoreGeneratedDiagram(d.kind, d.dot)
+		if err != nil {
+			return Output{}, err
+		}
+		out.Diagrams = append(out.Diagrams, id)
+	}
+	return out, nil
+}
+
+func removeAll(dir string) {
+	_ = os.RemoveAll(dir)
+}
@@ -0,0 +1,168 @@
+package ingest
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/archviz/backend-go/agent/render"
+)
+
+// Result lists the DOT source for each diagram type BuildIR produced.
+type Result struct {
+	Component  string
+	Package    string
+	Deployment string
+}
+
+// BuildIR assembles the three standard diagram types from an ingestion
+// run's raw analysis:
+//
+//   - Package: one node per Go package (and per non-Go service manifest),
+//     edges for Go imports that resolve to another package in modulePath.
+//   - Component: one node per deployment component, edges for depends_on
+//     and shared-volume relationships between them.
+//   - Deployment: the same components, one cluster per component kind
+//     (compose-service, k8s-deployment, k8s-statefulset, ...) so
+//     deployment targets are visually separated.
+//
+// A diagram type whose graph has no nodes is left as "".
+func BuildIR(pkgs []GoPackage, modulePath string, services []ServiceNode, components []ComponentNode, compEdges []ComponentEdge) Result {
+	emit := func(g *render.Graph) string {
+		if len(g.Nodes) == 0 {
+			return ""
+		}
+		return render.EmitDOT(g)
+	}
+	return Result{
+		Package:    emit(buildPackageGraph(pkgs, modulePath, services)),
+		Component:  emit(buildComponentGraph(components, compEdges)),
+		Deployment: emit(buildDeploymentGraph(components, compEdges)),
+	}
+}
+
+func buildPackageGraph(pkgs []GoPackage, modulePath string, services []ServiceNode) *render.Graph {
+	g := render.NewGraph(true)
+
+	for _, pkg := range pkgs {
+		id := nodeID("go", pkg.Dir)
+		n := g.Node(id)
+		n.Attrs["label"] = goPackageLabel(pkg, modulePath)
+		n.Attrs["shape"] = "box"
+		n.Attrs["color"] = languageColor("go")
+	}
+	for _, svc := range services {
+		id := nodeID(svc.Language, svc.Dir)
+		n := g.Node(id)
+		n.Attrs["label"] = svc.Name
+		n.Attrs["shape"] = "box"
+		n.Attrs["color"] = languageColor(svc.Language)
+	}
+
+	byImportPath := map[string]string{}
+	for _, pkg := range pkgs {
+		byImportPath[joinModulePath(modulePath, pkg.Dir)] = nodeID("go", pkg.Dir)
+	}
+	for _, pkg := range pkgs {
+		from := nodeID("go", pkg.Dir)
+		for _, imp := range pkg.Imports {
+			if to, ok := byImportPath[imp]; ok && to != from {
+				g.AddEdge(from, to, render.Attrs{"kind": "import"})
+			}
+		}
+	}
+	return g
+}
+
+func buildComponentGraph(components []ComponentNode, edges []ComponentEdge) *render.Graph {
+	g := render.NewGraph(true)
+	for _, c := range components {
+		n := g.Node(sanitizeID(c.Name))
+		n.Attrs["label"] = c.Name
+		n.Attrs["shape"] = "box3d"
+		n.Attrs["color"] = componentColor(c.Kind)
+		if c.Image != "" {
+			n.Attrs["tooltip"] = c.Image
+		}
+	}
+	for _, e := range edges {
+		g.AddEdge(sanitizeID(e.From), sanitizeID(e.To), render.Attrs{"kind": e.Kind})
+	}
+	return g
+}
+
+// buildDeploymentGraph groups components into clusters by kind (e.g.
+// compose services vs. Kubernetes workloads) so the deployment diagram
+// reads as "what runs where" rather than a flat component list.
+func buildDeploymentGraph(components []ComponentNode, edges []ComponentEdge) *render.Graph {
+	g := render.NewGraph(true)
+	clusters := map[string]*render.Cluster{}
+
+	for _, c := range components {
+		id := sanitizeID(c.Name)
+		n := g.Node(id)
+		n.Attrs["label"] = c.Name
+		n.Attrs["shape"] = "box3d"
+		n.Attrs["color"] = componentColor(c.Kind)
+		if c.Image != "" {
+			n.Attrs["tooltip"] = c.Image
+		}
+
+		cl, ok := clusters[c.Kind]
+		if !ok {
+			cl = &render.Cluster{ID: "cluster_" + sanitizeID(c.Kind), Attrs: render.Attrs{"label": c.Kind}}
+			clusters[c.Kind] = cl
+			g.Clusters = append(g.Clusters, cl)
+		}
+		cl.Nodes = append(cl.Nodes, id)
+	}
+	for _, e := range edges {
+		g.AddEdge(sanitizeID(e.From), sanitizeID(e.To), render.Attrs{"kind": e.Kind})
+	}
+	return g
+}
+
+func goPackageLabel(pkg GoPackage, modulePath string) string {
+	if pkg.Dir == "." {
+		return modulePath
+	}
+	return pkg.Dir
+}
+
+func joinModulePath(modulePath, dir string) string {
+	if dir == "." {
+		return modulePath
+	}
+	return modulePath + "/" + dir
+}
+
+func nodeID(prefix, dir string) string {
+	return sanitizeID(prefix + ":" + dir)
+}
+
+var idSanitizeRe = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+func sanitizeID(s string) string {
+	return idSanitizeRe.ReplaceAllString(s, "_")
+}
+
+func languageColor(lang string) string {
+	switch lang {
+	case "go":
+		return "#00add8"
+	case "node":
+		return "#68a063"
+	case "python":
+		return "#3776ab"
+	case "java":
+		return "#b07219"
+	default:
+		return "#6b7280"
+	}
+}
+
+func componentColor(kind string) string {
+	if strings.HasPrefix(kind, "k8s-") {
+		return "#326ce5"
+	}
+	return "#0db7ed"
+}
@@ -0,0 +1,87 @@
+package ingest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ServiceNode is a node inferred from a language-specific manifest file
+// (package.json, requirements.txt, pom.xml); one per directory containing
+// such a manifest, taken to mark the root of a deployable service.
+type ServiceNode struct {
+	Dir      string
+	Name     string
+	Language string
+}
+
+// DetectServiceManifests walks root looking for package.json,
+// requirements.txt and pom.xml files.
+func DetectServiceManifests(root string) ([]ServiceNode, error) {
+	var out []ServiceNode
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() {
+			if shouldSkipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		switch d.Name() {
+		case "package.json":
+			out = append(out, ServiceNode{Dir: rel, Name: nodePackageName(path, rel), Language: "node"})
+		case "requirements.txt":
+			out = append(out, ServiceNode{Dir: rel, Name: dirName(rel), Language: "python"})
+		case "pom.xml":
+			out = append(out, ServiceNode{Dir: rel, Name: mavenArtifactID(path, rel), Language: "java"})
+		}
+		return nil
+	})
+	return out, err
+}
+
+func nodePackageName(path, fallbackDir string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return dirName(fallbackDir)
+	}
+	var pkg struct {
+		Name string `json:"name"`
+	}
+	if json.Unmarshal(data, &pkg) == nil && pkg.Name != "" {
+		return pkg.Name
+	}
+	return dirName(fallbackDir)
+}
+
+func mavenArtifactID(path, fallbackDir string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return dirName(fallbackDir)
+	}
+	var pom struct {
+		ArtifactID string `xml:"artifactId"`
+	}
+	if xml.Unmarshal(data, &pom) == nil && pom.ArtifactID != "" {
+		return pom.ArtifactID
+	}
+	return dirName(fallbackDir)
+}
+
+func dirName(rel string) string {
+	if rel == "." || rel == "" {
+		return "root"
+	}
+	return filepath.Base(rel)
+}
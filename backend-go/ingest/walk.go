@@ -0,0 +1,14 @@
+package ingest
+
+var skipDirs = map[string]bool{
+	".git":         true,
+	"vendor":       true,
+	"node_modules": true,
+	".venv":        true,
+	"dist":         true,
+	"build":        true,
+}
+
+func shouldSkipDir(name string) bool {
+	return skipDirs[name]
+}
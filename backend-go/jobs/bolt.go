@@ -0,0 +1,165 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var jobsBucket = []byte("jobs")
+
+// BoltQueue is a Queue backed by a single BoltDB file, for single-node
+// deployments that need jobs to survive a restart. Scheduling (the ready
+// channel and subscriber fan-out) is still in-memory; only job state is
+// persisted, and queued jobs found on disk at startup are replayed onto
+// the ready channel so in-flight work isn't lost across a restart.
+type BoltQueue struct {
+	db *bolt.DB
+
+	mu    sync.Mutex
+	ready chan string
+	subs  map[string][]chan *Job
+}
+
+// OpenBoltQueue opens (creating if necessary) a BoltDB file at path and
+// requeues any jobs left in StatusQueued or StatusRunning from a prior
+// run.
+func OpenBoltQueue(path string, capacity int) (*BoltQueue, error) {
+	db, err := bolt.Open(path, 0o644, nil)
+	if err != nil {
+		return nil, fmt.Errorf("jobs: opening bolt db: %w", err)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(jobsBucket)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("jobs: preparing bolt bucket: %w", err)
+	}
+
+	if capacity <= 0 {
+		capacity = 64
+	}
+	q := &BoltQueue{db: db, ready: make(chan string, capacity), subs: map[string][]chan *Job{}}
+
+	var pending []string
+	if err := db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(jobsBucket)
+		return b.ForEach(func(k, v []byte) error {
+			var job Job
+			if err := json.Unmarshal(v, &job); err != nil {
+				return err
+			}
+			if job.Status == StatusQueued || job.Status == StatusRunning {
+				pending = append(pending, job.ID)
+			}
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	for _, id := range pending {
+		q.ready <- id
+	}
+	return q, nil
+}
+
+func (q *BoltQueue) Close() error {
+	return q.db.Close()
+}
+
+func (q *BoltQueue) put(job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return q.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Put([]byte(job.ID), data)
+	})
+}
+
+func (q *BoltQueue) Enqueue(ctx context.Context, job *Job) error {
+	if err := q.put(job); err != nil {
+		return err
+	}
+	select {
+	case q.ready <- job.ID:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *BoltQueue) Dequeue(ctx context.Context) (*Job, error) {
+	select {
+	case id := <-q.ready:
+		return q.get(id)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (q *BoltQueue) Update(ctx context.Context, job *Job) error {
+	if err := q.put(job); err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	subs := append([]chan *Job{}, q.subs[job.ID]...)
+	terminal := job.Status == StatusDone || job.Status == StatusFailed
+	if terminal {
+		delete(q.subs, job.ID)
+	}
+	q.mu.Unlock()
+
+	for _, ch := range subs {
+		ch <- job.Clone()
+		if terminal {
+			close(ch)
+		}
+	}
+	return nil
+}
+
+func (q *BoltQueue) get(id string) (*Job, error) {
+	var job *Job
+	err := q.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(jobsBucket).Get([]byte(id))
+		if v == nil {
+			return nil
+		}
+		job = &Job{}
+		return json.Unmarshal(v, job)
+	})
+	return job, err
+}
+
+func (q *BoltQueue) Get(ctx context.Context, id string) (*Job, bool, error) {
+	job, err := q.get(id)
+	if err != nil {
+		return nil, false, err
+	}
+	return job, job != nil, nil
+}
+
+func (q *BoltQueue) Subscribe(id string) (<-chan *Job, func()) {
+	ch := make(chan *Job, 8)
+	q.mu.Lock()
+	q.subs[id] = append(q.subs[id], ch)
+	q.mu.Unlock()
+
+	unsubscribe := func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		subs := q.subs[id]
+		for i, c := range subs {
+			if c == ch {
+				q.subs[id] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+	}
+	return ch, unsubscribe
+}
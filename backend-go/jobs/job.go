@@ -0,0 +1,39 @@
+// Package jobs provides a small persistent-capable job queue and worker
+// pool for background work (ingestion, rendering, GIF export) so callers
+// no longer spawn a bare goroutine per request with no retry, bound, or
+// durability across restarts.
+package jobs
+
+import "time"
+
+// Status is a Job's place in its lifecycle.
+type Status string
+
+const (
+	StatusQueued  Status = "queued"
+	StatusRunning Status = "running"
+	StatusDone    Status = "complete"
+	StatusFailed  Status = "failed"
+)
+
+// Job is a unit of background work. Payload and Result are free-form JSON
+// so each Kind's handler can define its own shape.
+type Job struct {
+	ID          string         `json:"job_id"`
+	Kind        string         `json:"kind"`
+	Payload     map[string]any `json:"payload"`
+	Status      Status         `json:"status"`
+	Attempts    int            `json:"attempts"`
+	MaxAttempts int            `json:"max_attempts"`
+	Result      map[string]any `json:"result,omitempty"`
+	Error       string         `json:"error,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// Clone returns a deep-enough copy for handing a Job to subscribers
+// without letting them mutate the queue's copy.
+func (j *Job) Clone() *Job {
+	cp := *j
+	return &cp
+}
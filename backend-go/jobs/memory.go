@@ -0,0 +1,111 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryQueue is an in-memory Queue backed by a buffered ready channel and
+// a map of job state. It is the default for single-process/dev use; jobs
+// do not survive a restart.
+type MemoryQueue struct {
+	mu    sync.Mutex
+	jobs  map[string]*Job
+	ready chan string
+	subs  map[string][]chan *Job
+}
+
+// NewMemoryQueue creates a MemoryQueue with room for `capacity` queued
+// jobs before Enqueue blocks.
+func NewMemoryQueue(capacity int) *MemoryQueue {
+	if capacity <= 0 {
+		capacity = 64
+	}
+	return &MemoryQueue{
+		jobs:  map[string]*Job{},
+		ready: make(chan string, capacity),
+		subs:  map[string][]chan *Job{},
+	}
+}
+
+func (q *MemoryQueue) Enqueue(ctx context.Context, job *Job) error {
+	q.mu.Lock()
+	q.jobs[job.ID] = job
+	q.mu.Unlock()
+	select {
+	case q.ready <- job.ID:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *MemoryQueue) Dequeue(ctx context.Context) (*Job, error) {
+	select {
+	case id := <-q.ready:
+		q.mu.Lock()
+		job := q.jobs[id]
+		q.mu.Unlock()
+		// Hand the caller its own copy: Pool.process mutates the job's
+		// fields directly (outside q.mu) as it runs, and only writes back
+		// through Update. Returning the map's own pointer here would let
+		// that unsynchronized mutation race with a concurrent Get/Clone.
+		return job.Clone(), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (q *MemoryQueue) Update(ctx context.Context, job *Job) error {
+	q.mu.Lock()
+	// Store a copy: the caller (Pool.process) keeps mutating job after
+	// Update returns.
+	q.jobs[job.ID] = job.Clone()
+	subs := append([]chan *Job{}, q.subs[job.ID]...)
+	terminal := job.Status == StatusDone || job.Status == StatusFailed
+	if terminal {
+		delete(q.subs, job.ID)
+	}
+	q.mu.Unlock()
+
+	for _, ch := range subs {
+		ch <- job.Clone()
+		if terminal {
+			close(ch)
+		}
+	}
+	return nil
+}
+
+func (q *MemoryQueue) Get(ctx context.Context, id string) (*Job, bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[id]
+	if !ok {
+		return nil, false, nil
+	}
+	// Clone so the caller's copy can't race with Pool.process mutating the
+	// job stored in q.jobs concurrently - same reason Subscribe hands out
+	// clones.
+	return job.Clone(), true, nil
+}
+
+func (q *MemoryQueue) Subscribe(id string) (<-chan *Job, func()) {
+	ch := make(chan *Job, 8)
+	q.mu.Lock()
+	q.subs[id] = append(q.subs[id], ch)
+	q.mu.Unlock()
+
+	unsubscribe := func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		subs := q.subs[id]
+		for i, c := range subs {
+			if c == ch {
+				q.subs[id] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+	}
+	return ch, unsubscribe
+}
@@ -0,0 +1,24 @@
+package jobs
+
+import "fmt"
+
+// Backend names accepted by the JOBS_BACKEND environment variable.
+const (
+	BackendMemory = "memory"
+	BackendBolt   = "bolt"
+)
+
+// Open constructs a Queue for the given backend. path is the BoltDB file
+// path when backend is BackendBolt; it is ignored otherwise. capacity is
+// the MemoryQueue ready-channel capacity, reused as BoltQueue's when that
+// backend is selected.
+func Open(backend, path string, capacity int) (Queue, error) {
+	switch backend {
+	case "", BackendMemory:
+		return NewMemoryQueue(capacity), nil
+	case BackendBolt:
+		return OpenBoltQueue(path, capacity)
+	default:
+		return nil, fmt.Errorf("jobs: unknown backend %q", backend)
+	}
+}
@@ -0,0 +1,23 @@
+package jobs
+
+import "context"
+
+// Queue is the storage + scheduling interface a Pool dequeues work from.
+// Implementations decide durability: MemoryQueue keeps jobs in a map and
+// loses them on restart, BoltQueue persists them to disk.
+type Queue interface {
+	// Enqueue stores a new job and makes it available to Dequeue.
+	Enqueue(ctx context.Context, job *Job) error
+	// Dequeue blocks until a queued job is available or ctx is done, then
+	// returns it unchanged; the caller (Pool.process) marks it running.
+	Dequeue(ctx context.Context) (*Job, error)
+	// Update persists a job's latest status/result/error and notifies
+	// anyone watching it via Subscribe.
+	Update(ctx context.Context, job *Job) error
+	// Get returns the current state of a job by ID.
+	Get(ctx context.Context, id string) (*Job, bool, error)
+	// Subscribe returns a channel of status updates for a single job and
+	// an unsubscribe func the caller must call when done watching. The
+	// channel is closed once the job reaches a terminal status.
+	Subscribe(id string) (<-chan *Job, func())
+}
@@ -0,0 +1,138 @@
+package jobs
+
+import (
+	"context"
+	"time"
+)
+
+// HandlerFunc does the actual work for one Job.Kind and returns its result
+// payload, or an error to trigger a retry (or final failure once
+// MaxAttempts is exhausted).
+type HandlerFunc func(ctx context.Context, job *Job) (map[string]any, error)
+
+const (
+	defaultMaxAttempts = 3
+	backoffBase        = 500 * time.Millisecond
+	backoffMax         = 30 * time.Second
+)
+
+// Recorder mirrors a job's status into longer-term storage (e.g. package
+// store) independently of Queue, so job history can be queried even
+// against the in-memory MemoryQueue backend.
+type Recorder interface {
+	PutJob(ctx context.Context, job *Job) error
+}
+
+// Pool runs a configurable number of worker goroutines pulling jobs off a
+// Queue and dispatching them to the handler registered for their Kind.
+type Pool struct {
+	queue       Queue
+	concurrency int
+	handlers    map[string]HandlerFunc
+	recorder    Recorder
+}
+
+// NewPool creates a worker pool over queue with the given concurrency
+// (number of jobs processed at once).
+func NewPool(queue Queue, concurrency int) *Pool {
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	return &Pool{queue: queue, concurrency: concurrency, handlers: map[string]HandlerFunc{}}
+}
+
+// RegisterHandler associates a Job.Kind with the function that processes
+// it. Call before Start; handlers are not safe to register concurrently
+// with a running pool.
+func (p *Pool) RegisterHandler(kind string, fn HandlerFunc) {
+	p.handlers[kind] = fn
+}
+
+// SetRecorder mirrors every job status transition to r in addition to
+// Queue. Optional; nil (the default) skips recording. Call before Start.
+func (p *Pool) SetRecorder(r Recorder) {
+	p.recorder = r
+}
+
+// Start launches the worker goroutines; they run until ctx is canceled.
+func (p *Pool) Start(ctx context.Context) {
+	for i := 0; i < p.concurrency; i++ {
+		go p.loop(ctx)
+	}
+}
+
+func (p *Pool) loop(ctx context.Context) {
+	for {
+		job, err := p.queue.Dequeue(ctx)
+		if err != nil {
+			return // ctx canceled
+		}
+		p.process(ctx, job)
+	}
+}
+
+// update persists job's current state to the Queue and, if one is set, to
+// the Recorder - callers always go through this rather than calling
+// p.queue.Update directly so a configured Recorder can't fall behind.
+func (p *Pool) update(ctx context.Context, job *Job) {
+	_ = p.queue.Update(ctx, job)
+	if p.recorder != nil {
+		_ = p.recorder.PutJob(ctx, job)
+	}
+}
+
+func (p *Pool) process(ctx context.Context, job *Job) {
+	job.Attempts++
+	job.Status = StatusRunning
+	job.UpdatedAt = time.Now()
+	p.update(ctx, job)
+
+	handler, ok := p.handlers[job.Kind]
+	if !ok {
+		job.Status = StatusFailed
+		job.Error = "jobs: no handler registered for kind " + job.Kind
+		job.UpdatedAt = time.Now()
+		p.update(ctx, job)
+		return
+	}
+
+	result, err := handler(ctx, job)
+	job.UpdatedAt = time.Now()
+	if err != nil {
+		maxAttempts := job.MaxAttempts
+		if maxAttempts <= 0 {
+			maxAttempts = defaultMaxAttempts
+		}
+		if job.Attempts < maxAttempts {
+			job.Status = StatusQueued
+			job.Error = err.Error()
+			p.update(ctx, job)
+			go p.retryAfterBackoff(ctx, job)
+			return
+		}
+		job.Status = StatusFailed
+		job.Error = err.Error()
+		p.update(ctx, job)
+		return
+	}
+
+	job.Status = StatusDone
+	job.Result = result
+	job.Error = ""
+	p.update(ctx, job)
+}
+
+// retryAfterBackoff re-enqueues job after an exponential delay based on
+// its attempt count, capped at backoffMax.
+func (p *Pool) retryAfterBackoff(ctx context.Context, job *Job) {
+	delay := backoffBase << uint(job.Attempts-1)
+	if delay > backoffMax {
+		delay = backoffMax
+	}
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+		return
+	}
+	_ = p.queue.Enqueue(ctx, job)
+}
@@ -2,19 +2,27 @@ package mcp
 
 import (
 	"encoding/json"
+	"log"
 	"net/http"
+	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/archviz/backend-go/agent"
+	"github.com/archviz/backend-go/store"
 )
 
-func NewHandlers(agent *agent.SimpleAgent) *Handlers {
-	return &Handlers{agent: agent}
+// NewHandlers wires up the MCP tool handlers. st is used only by ExportGIF,
+// to look up a session's prior diagram versions for an animated transition;
+// it may be nil for callers that never exercise that path.
+func NewHandlers(agent *agent.SimpleAgent, st store.Store) *Handlers {
+	return &Handlers{agent: agent, store: st}
 }
 
 type Handlers struct {
 	agent *agent.SimpleAgent
+	store store.Store
 }
 
 func (h *Handlers) Generate(w http.ResponseWriter, r *http.Request) {
@@ -61,8 +69,103 @@ func (h *Handlers) ExportSVG(w http.ResponseWriter, r *http.Request) {
 		http.ServeFile(w, r, matches[0])
 		return
 	}
-	// fallback: generate inline
-	svg := agent.GenerateSVG(id, "component")
+	// fallback: no rendered file on disk yet, render the IR inline
+	dot := h.agent.GetIR(id)["ir"].(string)
+	svg, err := h.agent.RenderDOT(dot)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	w.Header().Set("Content-Type", "image/svg+xml")
 	_, _ = w.Write([]byte(svg))
 }
+
+// ExportGIF renders an animated transition into the diagram's current
+// state as a GIF. The "from" snapshot is picked, in order of preference:
+// an explicit from_ir query param (a caller-supplied DOT string, for
+// previewing a transition that was never generated), or the diagram that
+// immediately precedes id in session_id's image history (each image's IR
+// is already persisted per image_id by agent.StoreIR/GetIR, so this reads
+// that history rather than requiring the caller to resupply it). With
+// neither available - id is the session's first diagram, or no session_id
+// was given - the animation grows the current diagram in from an empty
+// graph, the only transition that can be honestly produced from a single
+// IR snapshot.
+func (h *Handlers) ExportGIF(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/mcp/tool/export/gif/")
+	if id == "" {
+		http.Error(w, "missing id", http.StatusBadRequest)
+		return
+	}
+
+	toDOT := h.agent.GetIR(id)["ir"].(string)
+	q := r.URL.Query()
+	fromDOT := q.Get("from_ir")
+	if fromDOT == "" {
+		fromDOT = h.priorVersionIR(r, id, q.Get("session_id"))
+	}
+	if fromDOT == "" {
+		fromDOT = "digraph empty {}"
+	}
+
+	opts := agent.GIFOptions{
+		Frames:  intQuery(q, "frames", 0),
+		DelayMS: intQuery(q, "delay_ms", 0),
+		Width:   intQuery(q, "width", 0),
+		Height:  intQuery(q, "height", 0),
+	}
+
+	path, err := h.agent.ExportGIF(id, "component", fromDOT, toDOT, opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.ServeFile(w, r, path)
+}
+
+// priorVersionIR returns the stored IR of the diagram immediately
+// preceding imageID in sessionID's image history, or "" if there isn't
+// one (no store configured, no session_id given, the session or image
+// isn't found, or imageID is already the first image).
+func (h *Handlers) priorVersionIR(r *http.Request, imageID, sessionID string) string {
+	if h.store == nil || sessionID == "" {
+		return ""
+	}
+	s, ok, err := h.store.GetSession(r.Context(), sessionID)
+	if err != nil {
+		log.Printf("msg=gif_export_session_lookup_failed session_id=%s err=%v", sessionID, err)
+		return ""
+	}
+	if !ok {
+		return ""
+	}
+	for i, img := range s.Images {
+		if imgID, _ := img["id"].(string); imgID == imageID && i > 0 {
+			prevID, _ := s.Images[i-1]["id"].(string)
+			if prevID == "" {
+				return ""
+			}
+			// StoredIR, not GetIR: GetIR falls back to a fabricated
+			// placeholder digraph when nothing was persisted for prevID,
+			// which would otherwise get silently animated from as if it
+			// were the image's real prior state.
+			if dot, ok := h.agent.StoredIR(prevID); ok {
+				return dot
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+func intQuery(q url.Values, key string, fallback int) int {
+	v := q.Get(key)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
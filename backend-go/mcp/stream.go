@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/archviz/backend-go/agent"
+)
+
+// GenerateStream serves GET /mcp/tool/generate/stream as Server-Sent
+// Events, emitting one event per SimpleAgent.GenerateStream stage as the
+// diagram is produced instead of making the client wait for the whole
+// response.
+func (h *Handlers) GenerateStream(w http.ResponseWriter, r *http.Request) {
+	diagramType := r.URL.Query().Get("diagram_type")
+	if diagramType == "" {
+		diagramType = "diagram"
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	emit := func(ev agent.Event) {
+		writeSSEEvent(w, ev)
+		flusher.Flush()
+	}
+
+	// GenerateStream already emits an EventError for generation failures
+	// and checks r.Context() between stages, so there's nothing left to
+	// do with its returned error here.
+	_, _ = h.agent.GenerateStream(r.Context(), agent.GenerateRequest{DiagramType: diagramType}, emit)
+}
+
+func writeSSEEvent(w http.ResponseWriter, ev agent.Event) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data)
+}
@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/archviz/backend-go/agent"
+)
+
+// wsUpgrader allows connections from any origin, matching the permissive
+// CORS policy the rest of this API's HTTP routes already use.
+var wsUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// generateWSRequest is the single JSON message a client sends right after
+// the handshake to kick off a generation.
+type generateWSRequest struct {
+	DiagramType string `json:"diagram_type"`
+}
+
+// GenerateWS serves GET /ws/generate: it upgrades to a WebSocket, reads
+// one generateWSRequest, then writes one JSON agent.Event message per
+// GenerateStream stage before closing the connection.
+func (h *Handlers) GenerateWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("msg=ws_upgrade_failed err=%v", err)
+		return
+	}
+	defer conn.Close()
+
+	var req generateWSRequest
+	if err := conn.ReadJSON(&req); err != nil {
+		return
+	}
+	if req.DiagramType == "" {
+		req.DiagramType = "diagram"
+	}
+
+	emit := func(ev agent.Event) {
+		if err := conn.WriteJSON(ev); err != nil {
+			log.Printf("msg=ws_write_failed err=%v", err)
+		}
+	}
+
+	_, _ = h.agent.GenerateStream(r.Context(), agent.GenerateRequest{DiagramType: req.DiagramType}, emit)
+	_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
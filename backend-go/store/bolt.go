@@ -0,0 +1,158 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var sessionsBucket = []byte("sessions")
+var jobsBucket = []byte("jobs")
+
+// BoltStore is a Store backed by a single BoltDB file, for single-node
+// deployments that need sessions to survive a restart without standing
+// up a separate database.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// OpenBoltStore opens (creating if necessary) a BoltDB file at path.
+func OpenBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o644, nil)
+	if err != nil {
+		return nil, fmt.Errorf("store: opening bolt db: %w", err)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(sessionsBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(jobsBucket)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("store: preparing bolt bucket: %w", err)
+	}
+	return &BoltStore{db: db}, nil
+}
+
+func (b *BoltStore) CreateSession(ctx context.Context, s *Session) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(sessionsBucket)
+		if bucket.Get([]byte(s.ID)) != nil {
+			return fmt.Errorf("store: session %s already exists", s.ID)
+		}
+		return put(bucket, s)
+	})
+}
+
+func (b *BoltStore) GetSession(ctx context.Context, id string) (*Session, bool, error) {
+	var s *Session
+	err := b.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(sessionsBucket).Get([]byte(id))
+		if v == nil {
+			return nil
+		}
+		s = &Session{}
+		return json.Unmarshal(v, s)
+	})
+	return s, s != nil, err
+}
+
+func (b *BoltStore) UpdateSession(ctx context.Context, s *Session) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(sessionsBucket)
+		if bucket.Get([]byte(s.ID)) == nil {
+			return ErrNotFound
+		}
+		return put(bucket, s)
+	})
+}
+
+// AppendMessage reads, mutates and writes back the session within a
+// single Bolt transaction - Bolt serializes writers, so this can't lose a
+// concurrent append the way a separate Get+Update pair could.
+func (b *BoltStore) AppendMessage(ctx context.Context, id string, msg map[string]any) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(sessionsBucket)
+		v := bucket.Get([]byte(id))
+		if v == nil {
+			return ErrNotFound
+		}
+		var s Session
+		if err := json.Unmarshal(v, &s); err != nil {
+			return err
+		}
+		s.Messages = append(s.Messages, msg)
+		return put(bucket, &s)
+	})
+}
+
+// AppendImage is AppendMessage's counterpart for the Images list.
+func (b *BoltStore) AppendImage(ctx context.Context, id string, image map[string]any) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(sessionsBucket)
+		v := bucket.Get([]byte(id))
+		if v == nil {
+			return ErrNotFound
+		}
+		var s Session
+		if err := json.Unmarshal(v, &s); err != nil {
+			return err
+		}
+		s.Images = append(s.Images, image)
+		return put(bucket, &s)
+	})
+}
+
+func (b *BoltStore) PutJob(ctx context.Context, job *JobRecord) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		data, err := json.Marshal(job)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(jobsBucket).Put([]byte(job.ID), data)
+	})
+}
+
+func (b *BoltStore) GetJob(ctx context.Context, id string) (*JobRecord, bool, error) {
+	var job *JobRecord
+	err := b.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(jobsBucket).Get([]byte(id))
+		if v == nil {
+			return nil
+		}
+		job = &JobRecord{}
+		return json.Unmarshal(v, job)
+	})
+	return job, job != nil, err
+}
+
+func (b *BoltStore) ListJobsByStatus(ctx context.Context, status string) ([]*JobRecord, error) {
+	var out []*JobRecord
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(_, v []byte) error {
+			var job JobRecord
+			if err := json.Unmarshal(v, &job); err != nil {
+				return err
+			}
+			if job.Status == status {
+				out = append(out, &job)
+			}
+			return nil
+		})
+	})
+	return out, err
+}
+
+func (b *BoltStore) Close() error {
+	return b.db.Close()
+}
+
+func put(bucket *bolt.Bucket, s *Session) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(s.ID), data)
+}
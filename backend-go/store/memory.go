@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// MemoryStore keeps sessions in a map and loses them on restart - the
+// default backend, matching this backend's original in-memory behavior.
+type MemoryStore struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+	jobs     map[string]*JobRecord
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: map[string]*Session{}, jobs: map[string]*JobRecord{}}
+}
+
+func (m *MemoryStore) CreateSession(ctx context.Context, s *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.sessions[s.ID]; ok {
+		return fmt.Errorf("store: session %s already exists", s.ID)
+	}
+	cp := *s
+	m.sessions[s.ID] = &cp
+	return nil
+}
+
+func (m *MemoryStore) GetSession(ctx context.Context, id string) (*Session, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, false, nil
+	}
+	cp := *s
+	return &cp, true, nil
+}
+
+func (m *MemoryStore) UpdateSession(ctx context.Context, s *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.sessions[s.ID]; !ok {
+		return ErrNotFound
+	}
+	cp := *s
+	m.sessions[s.ID] = &cp
+	return nil
+}
+
+func (m *MemoryStore) AppendMessage(ctx context.Context, id string, msg map[string]any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return ErrNotFound
+	}
+	s.Messages = append(s.Messages, msg)
+	return nil
+}
+
+func (m *MemoryStore) AppendImage(ctx context.Context, id string, image map[string]any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return ErrNotFound
+	}
+	s.Images = append(s.Images, image)
+	return nil
+}
+
+func (m *MemoryStore) PutJob(ctx context.Context, job *JobRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *job
+	m.jobs[job.ID] = &cp
+	return nil
+}
+
+func (m *MemoryStore) GetJob(ctx context.Context, id string) (*JobRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return nil, false, nil
+	}
+	cp := *j
+	return &cp, true, nil
+}
+
+func (m *MemoryStore) ListJobsByStatus(ctx context.Context, status string) ([]*JobRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []*JobRecord
+	for _, j := range m.jobs {
+		if j.Status == status {
+			cp := *j
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+func (m *MemoryStore) Close() error {
+	return nil
+}
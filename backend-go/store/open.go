@@ -0,0 +1,26 @@
+package store
+
+import "fmt"
+
+// Backend names accepted by the STORE_BACKEND environment variable.
+const (
+	BackendMemory   = "memory"
+	BackendBolt     = "bolt"
+	BackendPostgres = "postgres"
+)
+
+// Open constructs a Store for the given backend. path is the BoltDB file
+// path when backend is BackendBolt, and dsn is the Postgres connection
+// string when backend is BackendPostgres; both are ignored otherwise.
+func Open(backend, path, dsn string) (Store, error) {
+	switch backend {
+	case "", BackendMemory:
+		return NewMemoryStore(), nil
+	case BackendBolt:
+		return OpenBoltStore(path)
+	case BackendPostgres:
+		return OpenPostgresStore(dsn)
+	default:
+		return nil, fmt.Errorf("store: unknown backend %q", backend)
+	}
+}
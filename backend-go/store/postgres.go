@@ -0,0 +1,229 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	_ "github.com/lib/pq"
+)
+
+// PostgresStore is a Store backed by a Postgres database, for
+// multi-instance deployments where BoltDB's single-file/single-process
+// model doesn't fit.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// OpenPostgresStore connects to dsn, applies any pending embedded
+// migrations, and returns a ready-to-use PostgresStore.
+func OpenPostgresStore(dsn string) (*PostgresStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("store: opening postgres: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("store: connecting to postgres: %w", err)
+	}
+	if err := migrate(db); err != nil {
+		return nil, err
+	}
+	return &PostgresStore{db: db}, nil
+}
+
+func (p *PostgresStore) CreateSession(ctx context.Context, s *Session) error {
+	messages, images, diagrams, plans, err := marshalLists(s)
+	if err != nil {
+		return err
+	}
+	_, err = p.db.ExecContext(ctx, `
+		INSERT INTO sessions (id, title, messages, images, diagrams, plans, source_repo, source_commit)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		s.ID, s.Title, messages, images, diagrams, plans, s.SourceRepo, s.SourceCommit)
+	if err != nil {
+		return fmt.Errorf("store: creating session %s: %w", s.ID, err)
+	}
+	return nil
+}
+
+func (p *PostgresStore) GetSession(ctx context.Context, id string) (*Session, bool, error) {
+	var s Session
+	var messages, images, diagrams, plans []byte
+	s.ID = id
+	err := p.db.QueryRowContext(ctx, `
+		SELECT title, messages, images, diagrams, plans, source_repo, source_commit
+		FROM sessions WHERE id = $1`, id).
+		Scan(&s.Title, &messages, &images, &diagrams, &plans, &s.SourceRepo, &s.SourceCommit)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("store: getting session %s: %w", id, err)
+	}
+	if err := unmarshalLists(&s, messages, images, diagrams, plans); err != nil {
+		return nil, false, err
+	}
+	return &s, true, nil
+}
+
+func (p *PostgresStore) UpdateSession(ctx context.Context, s *Session) error {
+	messages, images, diagrams, plans, err := marshalLists(s)
+	if err != nil {
+		return err
+	}
+	res, err := p.db.ExecContext(ctx, `
+		UPDATE sessions SET title = $2, messages = $3, images = $4, diagrams = $5,
+			plans = $6, source_repo = $7, source_commit = $8, updated_at = now()
+		WHERE id = $1`,
+		s.ID, s.Title, messages, images, diagrams, plans, s.SourceRepo, s.SourceCommit)
+	if err != nil {
+		return fmt.Errorf("store: updating session %s: %w", s.ID, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// AppendMessage concatenates msg onto the session's messages column in a
+// single statement (jsonb ||), so two concurrent appends (even from
+// separate instances) both land instead of one clobbering the other the
+// way a SELECT-then-UPDATE round trip would.
+func (p *PostgresStore) AppendMessage(ctx context.Context, id string, msg map[string]any) error {
+	return p.appendToList(ctx, id, "messages", msg)
+}
+
+// AppendImage is AppendMessage's counterpart for the images column.
+func (p *PostgresStore) AppendImage(ctx context.Context, id string, image map[string]any) error {
+	return p.appendToList(ctx, id, "images", image)
+}
+
+func (p *PostgresStore) appendToList(ctx context.Context, id, column string, item map[string]any) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	res, err := p.db.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE sessions SET %s = %s || $2::jsonb, updated_at = now()
+		WHERE id = $1`, column, column),
+		id, data)
+	if err != nil {
+		return fmt.Errorf("store: appending to %s for session %s: %w", column, id, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (p *PostgresStore) PutJob(ctx context.Context, job *JobRecord) error {
+	result, err := json.Marshal(job.Result)
+	if err != nil {
+		return err
+	}
+	_, err = p.db.ExecContext(ctx, `
+		INSERT INTO jobs (id, kind, payload, status, attempts, result, error, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO UPDATE SET
+			kind = EXCLUDED.kind, payload = EXCLUDED.payload, status = EXCLUDED.status,
+			attempts = EXCLUDED.attempts, result = EXCLUDED.result, error = EXCLUDED.error,
+			updated_at = EXCLUDED.updated_at`,
+		job.ID, job.Kind, marshalPayload(job.Payload), job.Status, job.Attempts, result, job.Error, job.CreatedAt, job.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("store: putting job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+func (p *PostgresStore) GetJob(ctx context.Context, id string) (*JobRecord, bool, error) {
+	job := &JobRecord{ID: id}
+	var payload, result []byte
+	err := p.db.QueryRowContext(ctx, `
+		SELECT kind, payload, status, attempts, result, error, created_at, updated_at
+		FROM jobs WHERE id = $1`, id).
+		Scan(&job.Kind, &payload, &job.Status, &job.Attempts, &result, &job.Error, &job.CreatedAt, &job.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("store: getting job %s: %w", id, err)
+	}
+	if err := json.Unmarshal(payload, &job.Payload); err != nil {
+		return nil, false, err
+	}
+	if len(result) > 0 {
+		if err := json.Unmarshal(result, &job.Result); err != nil {
+			return nil, false, err
+		}
+	}
+	return job, true, nil
+}
+
+func (p *PostgresStore) ListJobsByStatus(ctx context.Context, status string) ([]*JobRecord, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT id, kind, payload, status, attempts, result, error, created_at, updated_at
+		FROM jobs WHERE status = $1`, status)
+	if err != nil {
+		return nil, fmt.Errorf("store: listing jobs with status %s: %w", status, err)
+	}
+	defer rows.Close()
+
+	var out []*JobRecord
+	for rows.Next() {
+		job := &JobRecord{}
+		var payload, result []byte
+		if err := rows.Scan(&job.ID, &job.Kind, &payload, &job.Status, &job.Attempts, &result, &job.Error, &job.CreatedAt, &job.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(payload, &job.Payload); err != nil {
+			return nil, err
+		}
+		if len(result) > 0 {
+			if err := json.Unmarshal(result, &job.Result); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, job)
+	}
+	return out, rows.Err()
+}
+
+func marshalPayload(payload map[string]any) []byte {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return []byte("{}")
+	}
+	return data
+}
+
+func (p *PostgresStore) Close() error {
+	return p.db.Close()
+}
+
+func marshalLists(s *Session) (messages, images, diagrams, plans []byte, err error) {
+	if messages, err = json.Marshal(s.Messages); err != nil {
+		return
+	}
+	if images, err = json.Marshal(s.Images); err != nil {
+		return
+	}
+	if diagrams, err = json.Marshal(s.Diagrams); err != nil {
+		return
+	}
+	plans, err = json.Marshal(s.Plans)
+	return
+}
+
+func unmarshalLists(s *Session, messages, images, diagrams, plans []byte) error {
+	if err := json.Unmarshal(messages, &s.Messages); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(images, &s.Images); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(diagrams, &s.Diagrams); err != nil {
+		return err
+	}
+	return json.Unmarshal(plans, &s.Plans)
+}
@@ -0,0 +1,84 @@
+// Package store persists the backend's session state behind a pluggable
+// Store interface, selected at startup via the STORE_BACKEND environment
+// variable (memory, bolt, postgres) so sessions and their attached
+// images/diagrams survive a restart instead of living only in an
+// in-memory map.
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by Get/Update-style lookups for a missing ID.
+var ErrNotFound = errors.New("store: not found")
+
+// Session is the persisted form of a UI session: its messages, generated
+// images/diagrams, and - once ingestion has run - the repo it covers.
+type Session struct {
+	ID           string           `json:"session_id"`
+	Title        string           `json:"title"`
+	Messages     []map[string]any `json:"messages"`
+	Images       []map[string]any `json:"images"`
+	Diagrams     []map[string]any `json:"diagrams"`
+	Plans        []map[string]any `json:"plans"`
+	SourceRepo   *string          `json:"source_repo"`
+	SourceCommit *string          `json:"source_commit"`
+}
+
+// JobRecord is the persisted form of a background job's status, mirroring
+// package jobs' Job shape. It's a separate type (rather than an import of
+// jobs.Job) so store has no dependency on the scheduling package: jobs.Pool
+// can record through this for a queryable history that survives a
+// restart even when running against the in-memory jobs.Queue.
+type JobRecord struct {
+	ID        string         `json:"job_id"`
+	Kind      string         `json:"kind"`
+	Payload   map[string]any `json:"payload"`
+	Status    string         `json:"status"`
+	Attempts  int            `json:"attempts"`
+	Result    map[string]any `json:"result,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// Store is the persistence boundary for Session state. Implementations
+// must be safe for concurrent use.
+type Store interface {
+	// CreateSession inserts a new session. It errors if s.ID already exists.
+	CreateSession(ctx context.Context, s *Session) error
+	// GetSession returns the session with the given ID, or ok=false if
+	// none exists.
+	GetSession(ctx context.Context, id string) (s *Session, ok bool, err error)
+	// UpdateSession persists s's current field values, overwriting the
+	// stored copy. It errors with ErrNotFound if s.ID doesn't exist.
+	//
+	// UpdateSession is a blind overwrite: it's for fields a caller owns
+	// exclusively (Title, SourceRepo, SourceCommit), not for appending to
+	// Messages/Images/Diagrams/Plans - a caller doing its own
+	// read-modify-write on those loses concurrent appends from another
+	// request. Use AppendMessage/AppendImage for those instead.
+	UpdateSession(ctx context.Context, s *Session) error
+	// AppendMessage atomically adds msg to the session's Messages list. It
+	// errors with ErrNotFound if id doesn't exist.
+	AppendMessage(ctx context.Context, id string, msg map[string]any) error
+	// AppendImage atomically adds image to the session's Images list. It
+	// errors with ErrNotFound if id doesn't exist.
+	AppendImage(ctx context.Context, id string, image map[string]any) error
+
+	// PutJob inserts or overwrites a job record, keyed by job.ID, so job
+	// status survives a restart and can be queried independently of the
+	// in-memory scheduling queue in package jobs.
+	PutJob(ctx context.Context, job *JobRecord) error
+	// GetJob returns the job record with the given ID, or ok=false if none
+	// exists.
+	GetJob(ctx context.Context, id string) (job *JobRecord, ok bool, err error)
+	// ListJobsByStatus returns all job records currently in status.
+	ListJobsByStatus(ctx context.Context, status string) ([]*JobRecord, error)
+
+	// Close releases any resources (file handles, connection pools) held
+	// by the store.
+	Close() error
+}